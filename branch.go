@@ -2,42 +2,49 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/gitty/vcs"
-	"github.com/muesli/reflow/truncate"
 )
 
-func printBranch(branch vcs.Branch, stat *trackStat, maxWidth int) {
+func printBranch(w io.Writer, branch vcs.Branch, stat *trackStat, l tableLayout, changed bool) {
 	genericStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGray))
 	numberStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorBlue)).Width(maxWidth)
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth)
 	authorStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorBlue))
 	timeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorGreen)).Width(8).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(70 - maxWidth)
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
 
 	var s string
+	s += renderMarker(l, changed)
 	s += numberStyle.Render(branch.Name)
+	if l.showStat {
+		s += genericStyle.Render(" ")
+		s += stat.Render()
+	}
 	s += genericStyle.Render(" ")
-	s += stat.Render()
-	s += genericStyle.Render(" ")
-	s += titleStyle.Render(truncate.StringWithTail(branch.LastCommit.MessageHeadline, uint(70-maxWidth), "…"))
-	s += genericStyle.Render(" ")
-	s += timeStyle.Render(ago(branch.LastCommit.CommittedAt))
-	s += genericStyle.Render(" ")
-	s += authorStyle.Render(branch.LastCommit.Author)
+	s += titleStyle.Render(truncateString(branch.LastCommit.MessageHeadline, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(branch.LastCommit.CommittedAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += authorStyle.Render(truncateString(branch.LastCommit.Author, l.extraWidth))
+	}
 
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 }
 
-func printBranches(branches []vcs.Branch, stats map[string]*trackStat) {
+func printBranches(w io.Writer, branches []vcs.Branch, stats map[string]*trackStat, hl *changeSet) {
 	headerStyle := lipgloss.NewStyle().
 		PaddingTop(1).
 		Foreground(lipgloss.Color(theme.colorMagenta))
@@ -48,22 +55,26 @@ func printBranches(branches []vcs.Branch, stats map[string]*trackStat) {
 		// trimmed = true
 	}
 
-	fmt.Println(headerStyle.Render(fmt.Sprintf("%s %s", "🌳", pluralize(len(branches), "active branch", "active branches"))))
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🌳", pluralize(len(branches), "active branch", "active branches"))))
 
-	// detect max width of branch name
-	var maxWidth int
+	// detect max width of branch name and author
+	var maxWidth, authorWidth int
 	for _, v := range branches {
 		if len(v.Name) > maxWidth {
 			maxWidth = len(v.Name)
 		}
+		if len(v.LastCommit.Author) > authorWidth {
+			authorWidth = len(v.LastCommit.Author)
+		}
 	}
+	l := newTableLayout(maxWidth, true, authorWidth).withMarker(hl != nil)
 
 	for _, v := range branches {
 		stat, ok := stats[v.Name]
 		if !ok {
 			stat = nil
 		}
-		printBranch(v, stat, maxWidth)
+		printBranch(w, v, stat, l, hl.branch(v.Name))
 	}
 	// if trimmed {
 	// 	fmt.Println("...")
@@ -82,7 +93,7 @@ func filterBranches(branches []vcs.Branch) []vcs.Branch {
 	var b []vcs.Branch //nolint
 	for _, v := range branches {
 		if *maxBranchAge > 0 &&
-			v.LastCommit.CommittedAt.Before(time.Now().Add(-24*time.Duration(*maxBranchAge)*time.Hour)) {
+			v.LastCommit.CommittedAt.Before(now().Add(-24*time.Duration(*maxBranchAge)*time.Hour)) {
 			continue
 		}
 		b = append(b, v)
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+const markerWidth = 2
+
+// changeSet tracks which items of an overview are new or changed.
+type changeSet struct {
+	issues       map[int]bool
+	pullRequests map[int]bool
+	branches     map[string]bool
+	commits      map[string]bool
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{
+		issues:       map[int]bool{},
+		pullRequests: map[int]bool{},
+		branches:     map[string]bool{},
+		commits:      map[string]bool{},
+	}
+}
+
+// diffOverview returns the items of cur that are new or changed compared to
+// prev. All items of cur are considered unchanged if there is no prev.
+// newestIssue and newestPullRequest mark the newest items ever shown, which
+// may no longer be part of prev.
+func diffOverview(prev, cur *Overview, newestIssue, newestPullRequest listMark) *changeSet {
+	c := newChangeSet()
+	if prev == nil {
+		return c
+	}
+
+	// lists only hold the latest issues and pull requests, so an older item
+	// moving up into a list when another one gets closed isn't new
+	issues := map[int]bool{}
+	for _, v := range prev.Issues {
+		issues[v.ID] = true
+		newestIssue.add(v.ID, v.CreatedAt)
+	}
+	for _, v := range cur.Issues {
+		if !issues[v.ID] && newestIssue.olderThan(v.ID, v.CreatedAt) {
+			c.issues[v.ID] = true
+		}
+	}
+
+	prs := map[int]bool{}
+	for _, v := range prev.PullRequests {
+		prs[v.ID] = true
+		newestPullRequest.add(v.ID, v.CreatedAt)
+	}
+	for _, v := range cur.PullRequests {
+		if !prs[v.ID] && newestPullRequest.olderThan(v.ID, v.CreatedAt) {
+			c.pullRequests[v.ID] = true
+		}
+	}
+
+	branches := map[string]string{}
+	for _, v := range prev.Branches {
+		branches[v.Name] = v.LastCommit.ID
+	}
+	for _, v := range cur.Branches {
+		if id, ok := branches[v.Name]; !ok || id != v.LastCommit.ID {
+			c.branches[v.Name] = true
+		}
+	}
+
+	c.addNewCommits(prev.Commits, cur.Commits)
+	return c
+}
+
+// listMark is the newest item of a list of issues or pull requests.
+type listMark struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// olderThan reports whether the mark is older than the item with the given ID
+// and creation time. IDs decide if a creation time is unknown.
+func (m listMark) olderThan(id int, createdAt time.Time) bool {
+	if m.CreatedAt.IsZero() || createdAt.IsZero() || createdAt.Equal(m.CreatedAt) {
+		return id > m.ID
+	}
+	return createdAt.After(m.CreatedAt)
+}
+
+// add moves the mark to the given item if it's newer. Marks with a creation
+// time win over the IDs recorded by older snapshots.
+func (m *listMark) add(id int, createdAt time.Time) {
+	switch {
+	case createdAt.IsZero() && !m.CreatedAt.IsZero():
+		// keep the dated mark
+	case m.CreatedAt.IsZero() && !createdAt.IsZero(), m.olderThan(id, createdAt):
+		m.ID, m.CreatedAt = id, createdAt
+	}
+}
+
+// addNewCommits marks all commits of cur that aren't part of prev.
+func (c *changeSet) addNewCommits(prev, cur []vcs.Commit) {
+	commits := map[string]bool{}
+	for _, v := range prev {
+		commits[v.ID] = true
+	}
+	for _, v := range cur {
+		if !commits[v.ID] {
+			c.commits[v.ID] = true
+		}
+	}
+}
+
+func (c *changeSet) issue(id int) bool {
+	return c != nil && c.issues[id]
+}
+
+func (c *changeSet) pullRequest(id int) bool {
+	return c != nil && c.pullRequests[id]
+}
+
+func (c *changeSet) branch(name string) bool {
+	return c != nil && c.branches[name]
+}
+
+func (c *changeSet) commit(id string) bool {
+	return c != nil && c.commits[id]
+}
+
+// renderMarker returns the indicator for new or changed items. Lists without
+// a changeSet don't get a marker column at all.
+func renderMarker(l tableLayout, changed bool) string {
+	if !l.marker {
+		return ""
+	}
+
+	markerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorYellow)).Width(markerWidth)
+	if changed {
+		return markerStyle.Render("●")
+	}
+	return markerStyle.Render("")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestDiffOverview(t *testing.T) {
+	prev := testOverview()
+	cur := testOverview()
+
+	if c := diffOverview(nil, cur, listMark{}, listMark{}); len(c.issues)+len(c.pullRequests)+len(c.branches)+len(c.commits) > 0 {
+		t.Errorf("Expected no changes without previous state, got %+v", c)
+	}
+
+	cur.Issues = append(cur.Issues, vcs.Issue{ID: 99, Title: "New issue", CreatedAt: fixedNow})
+	cur.IssueCount = len(cur.Issues)
+	cur.PullRequests = cur.PullRequests[1:]
+	cur.PullRequestCount = len(cur.PullRequests)
+	cur.Branches[1].LastCommit.ID = "abcdef"
+	cur.Commits = append([]vcs.Commit{{ID: "abcdef", MessageHeadline: "New commit", CommittedAt: fixedNow}}, cur.Commits...)
+	cur.Repo.LastRelease.CommitsSince = cur.Commits
+	cur.Repo.LastRelease.CommitCount = len(cur.Commits)
+
+	c := diffOverview(prev, cur, listMark{}, listMark{})
+	if !c.issue(99) || c.issue(56) {
+		t.Errorf("Unexpected issue changes: %v", c.issues)
+	}
+	if len(c.pullRequests) != 0 {
+		t.Errorf("Unexpected pull request changes: %v", c.pullRequests)
+	}
+	if !c.branch("feature/long-branch-name") || c.branch("master") {
+		t.Errorf("Unexpected branch changes: %v", c.branches)
+	}
+	if !c.commit("abcdef") || len(c.commits) != 1 {
+		t.Errorf("Unexpected commit changes: %v", c.commits)
+	}
+
+	// an issue older than the newest one ever shown isn't new, even if the
+	// newest one has been closed since
+	mark := listMark{ID: 100, CreatedAt: fixedNow.Add(time.Hour)}
+	if c := diffOverview(prev, cur, mark, listMark{}); c.issue(99) {
+		t.Error("Expected an issue older than the mark not to be new")
+	}
+
+	var nilChanges *changeSet
+	if nilChanges.issue(99) {
+		t.Error("Expected nil changeSet to report no changes")
+	}
+
+	setupRenderTest(t, "dark")
+	cur.changes = c
+
+	var buf bytes.Buffer
+	printOverview(&buf, cur)
+	assertGolden(t, "overview_changes", buf.Bytes())
+}
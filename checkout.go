@@ -0,0 +1,288 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// pullRequestBranch returns the name of the local branch a pull request gets
+// checked out to.
+func pullRequestBranch(number int) string {
+	return fmt.Sprintf("pr-%d", number)
+}
+
+// remoteAuth returns the credentials to fetch from a remote. Only HTTPS remotes
+// need a token, SSH remotes use the SSH agent.
+func remoteAuth(host, remoteURL string) transport.AuthMethod {
+	if !strings.HasPrefix(remoteURL, "https://") && !strings.HasPrefix(remoteURL, "http://") {
+		return nil
+	}
+	token := tokenForHost(host)
+	if token == "" {
+		return nil
+	}
+
+	// the user name is ignored, but mustn't be empty
+	return &http.BasicAuth{Username: "gitty", Password: token}
+}
+
+// hasLocalChanges returns whether the worktree contains uncommitted changes to
+// tracked files.
+func hasLocalChanges(wt *git.Worktree) (bool, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range status {
+		if s.Worktree == git.Untracked {
+			continue
+		}
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pullRequestHead describes where the commits of a pull request come from.
+type pullRequestHead struct {
+	// Ref points at the head of the pull request on the base repository.
+	Ref string
+	// Branch is the branch of the pull request on the head repository.
+	Branch string
+	// Remote is the name of the head repository's remote, URL its clone URL.
+	// Remote is empty if the head repository has been deleted.
+	Remote string
+	URL    string
+}
+
+// checkoutPullRequest fetches the head of a pull request from remote and checks
+// it out as a local branch, tracking the branch on the head repository. A
+// remote for forks gets added if necessary. An existing branch only gets
+// fast-forwarded. It returns the name of the branch.
+func checkoutPullRequest(path, remote string, number int, head pullRequestHead, auth transport.AuthMethod) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	dirty, err := hasLocalChanges(wt)
+	if err != nil {
+		return "", err
+	}
+	if dirty {
+		return "", fmt.Errorf("can't check out pull request %d: worktree contains uncommitted changes", number)
+	}
+
+	branch := pullRequestBranch(number)
+	if head.Remote != "" && head.Remote != remote {
+		head.Remote, err = forkRemote(repo, head.Remote, head.URL)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// the pull request's ref on the base repository also works for forks
+	// we can't access, so fetch it in place of the head branch
+	trackingRef := plumbing.NewRemoteReferenceName(remote, branch)
+	if head.Remote != "" {
+		trackingRef = plumbing.NewRemoteReferenceName(head.Remote, head.Branch)
+	}
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec("+" + head.Ref + ":" + trackingRef.String())},
+		Auth:       auth,
+		Tags:       git.NoTags,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", fmt.Errorf("can't fetch pull request %d: %v", number, err)
+	}
+
+	fetched, err := repo.Reference(trackingRef, true)
+	if err != nil {
+		return "", err
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	if existing, err := repo.Reference(branchRef, true); err == nil {
+		if err := canFastForward(repo, existing.Hash(), fetched.Hash()); err != nil {
+			return "", fmt.Errorf("can't update branch %s: %v", branch, err)
+		}
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, fetched.Hash())); err != nil {
+		return "", err
+	}
+
+	if head.Remote != "" {
+		cfg, err := repo.Config()
+		if err != nil {
+			return "", err
+		}
+		cfg.Branches[branch] = &gitconfig.Branch{
+			Name:   branch,
+			Remote: head.Remote,
+			Merge:  plumbing.NewBranchReferenceName(head.Branch),
+		}
+		if err := repo.SetConfig(cfg); err != nil {
+			return "", err
+		}
+	}
+
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: branchRef}); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+// forkRemote returns the name of the remote pointing at a fork's URL, adding
+// it if necessary. New remotes get the given name, or a numbered variant of it
+// if a remote with that name points elsewhere.
+func forkRemote(repo *git.Repository, name, u string) (string, error) {
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return "", err
+	}
+	taken := map[string]bool{}
+	for _, r := range remotes {
+		for _, v := range r.Config().URLs {
+			if sameRemoteURL(v, u) {
+				return r.Config().Name, nil
+			}
+		}
+		taken[r.Config().Name] = true
+	}
+
+	rn := name
+	for i := 2; taken[rn]; i++ {
+		rn = fmt.Sprintf("%s-%d", name, i)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: rn,
+		URLs: []string{u},
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't add remote %s: %v", rn, err)
+	}
+	return rn, nil
+}
+
+// sameRemoteURL returns whether two remote URLs point at the same repository,
+// e.g. its SSH and HTTPS URLs.
+func sameRemoteURL(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ca, err := cleanupURL(a)
+	if err != nil {
+		return false
+	}
+	cb, err := cleanupURL(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ca, cb)
+}
+
+// canFastForward returns an error unless from is an ancestor of to.
+func canFastForward(repo *git.Repository, from, to plumbing.Hash) error {
+	if from == to {
+		return nil
+	}
+
+	fc, err := repo.CommitObject(from)
+	if err != nil {
+		return err
+	}
+	tc, err := repo.CommitObject(to)
+	if err != nil {
+		return err
+	}
+	ok, err := fc.IsAncestor(tc)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("it has diverged from the pull request")
+	}
+	return nil
+}
+
+// parseCheckout checks out a pull request of the repository in the current
+// directory.
+func parseCheckout(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: gitty checkout NUMBER")
+		os.Exit(1)
+	}
+	num, err := strconv.Atoi(args[0])
+	if err != nil || num <= 0 {
+		fmt.Printf("Not a pull request number: %s\n", args[0])
+		os.Exit(1)
+	}
+
+	host, owner, name, rn, err := parseRepo(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if rn == "" {
+		fmt.Println("not a local git repository")
+		os.Exit(1)
+	}
+	client, err := guessClient(host)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// the remote's URL as configured, not cleaned up like parseRepo's
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	remote, err := repo.Remote(rn)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	auth := remoteAuth(host, remote.Config().URLs[0])
+
+	pr, err := client.PullRequest(owner, name, num)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	head := pullRequestHead{
+		Ref:    client.PullRequestRef(num),
+		Branch: pr.Branch,
+	}
+	switch {
+	case strings.EqualFold(pr.HeadRepo, owner+"/"+name):
+		head.Remote = rn
+	case pr.HeadRepo != "":
+		// name the remote of a fork after its owner
+		head.Remote = path.Dir(pr.HeadRepo)
+		head.URL = pr.HeadCloneURL
+	}
+
+	branch, err := checkoutPullRequest(".", rn, num, head, auth)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Switched to branch %s\n", branch)
+}
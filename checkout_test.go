@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFile commits a file to the checked out branch of the repository at
+// dir and returns the commit's SHA.
+func commitFile(t *testing.T, dir, name, content string) plumbing.Hash {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, name), content)
+	if _, err := wt.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	h, err := wt.Commit("Update "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "muesli", Email: "muesli@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// push force-pushes the refspec from the repository at dir to origin.
+func push(t *testing.T, dir, refspec string) {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Push(&git.PushOptions{
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec("+" + refspec)},
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		t.Fatal(err)
+	}
+}
+
+// setupPullRequestRemote creates a bare repository acting as the remote, with
+// a contributor's feature branch pushed to it and to refs/pull/42/head, like
+// hosts do for pull requests. It returns the contributor's checkout and a
+// clone of the remote.
+func setupPullRequestRemote(t *testing.T) (string, string) {
+	t.Helper()
+
+	remote := t.TempDir()
+	if _, err := git.PlainInit(remote, true); err != nil {
+		t.Fatal(err)
+	}
+
+	contrib, _ := initTestRepo(t, "master")
+	repo, err := git.PlainOpen(contrib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{remote},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	push(t, contrib, "refs/heads/master:refs/heads/master")
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName("feature"),
+		Create: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, contrib, "feature.txt", "first")
+	push(t, contrib, "refs/heads/feature:refs/heads/feature")
+	push(t, contrib, "refs/heads/feature:refs/pull/42/head")
+
+	clone := t.TempDir()
+	if _, err := git.PlainClone(clone, false, &git.CloneOptions{URL: remote}); err != nil {
+		t.Fatal(err)
+	}
+	return contrib, clone
+}
+
+func assertCheckedOut(t *testing.T, dir, branch string, hash plumbing.Hash) {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Name() != plumbing.NewBranchReferenceName(branch) {
+		t.Errorf("Expected %s to be checked out, got %s", branch, head.Name())
+	}
+	if head.Hash() != hash {
+		t.Errorf("Expected %s to point at %s, got %s", branch, hash, head.Hash())
+	}
+}
+
+func assertTracking(t *testing.T, dir, branch, remote, merge string) {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := repo.Branch(branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Remote != remote || b.Merge.String() != merge {
+		t.Errorf("Expected %s to track %s %s, got %s %s", branch, remote, merge, b.Remote, b.Merge)
+	}
+}
+
+func headHash(t *testing.T, dir string) plumbing.Hash {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head.Hash()
+}
+
+func TestCheckoutPullRequest(t *testing.T) {
+	contrib, clone := setupPullRequestRemote(t)
+	head := pullRequestHead{
+		Ref:    "refs/pull/42/head",
+		Branch: "feature",
+		Remote: "origin",
+	}
+
+	branch, err := checkoutPullRequest(clone, "origin", 42, head, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "pr-42" {
+		t.Errorf("Expected branch pr-42, got %s", branch)
+	}
+	assertCheckedOut(t, clone, "pr-42", headHash(t, contrib))
+	assertTracking(t, clone, "pr-42", "origin", "refs/heads/feature")
+
+	// new commits get fast-forwarded
+	h := commitFile(t, contrib, "feature.txt", "second")
+	push(t, contrib, "refs/heads/feature:refs/pull/42/head")
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertCheckedOut(t, clone, "pr-42", h)
+}
+
+func TestCheckoutPullRequestFromFork(t *testing.T) {
+	contrib, clone := setupPullRequestRemote(t)
+	fork := t.TempDir()
+	head := pullRequestHead{
+		Ref:    "refs/pull/42/head",
+		Branch: "feature",
+		Remote: "contrib",
+		URL:    fork,
+	}
+
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertCheckedOut(t, clone, "pr-42", headHash(t, contrib))
+	assertTracking(t, clone, "pr-42", "contrib", "refs/heads/feature")
+
+	// a remote for the fork got added
+	repo, err := git.PlainOpen(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := repo.Remote("contrib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := r.Config().URLs[0]; u != fork {
+		t.Errorf("Expected remote contrib to point at %s, got %s", fork, u)
+	}
+	if _, err := repo.Reference("refs/remotes/contrib/feature", true); err != nil {
+		t.Errorf("Expected the head branch to be tracked: %v", err)
+	}
+}
+
+func TestCheckoutPullRequestRemoteNameTaken(t *testing.T) {
+	contrib, clone := setupPullRequestRemote(t)
+	fork := t.TempDir()
+	head := pullRequestHead{
+		Ref:    "refs/pull/42/head",
+		Branch: "feature",
+		Remote: "contrib",
+		URL:    fork,
+	}
+
+	// a remote named after the fork's owner points at another repository
+	repo, err := git.PlainOpen(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: "contrib",
+		URLs: []string{t.TempDir()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertCheckedOut(t, clone, "pr-42", headHash(t, contrib))
+	assertTracking(t, clone, "pr-42", "contrib-2", "refs/heads/feature")
+
+	// an existing remote for the fork gets reused, whatever its name
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertTracking(t, clone, "pr-42", "contrib-2", "refs/heads/feature")
+	if _, err := repo.Remote("contrib-3"); !errors.Is(err, git.ErrRemoteNotFound) {
+		t.Errorf("Expected no further remote, got %v", err)
+	}
+}
+
+func TestCheckoutPullRequestDeletedFork(t *testing.T) {
+	contrib, clone := setupPullRequestRemote(t)
+
+	if _, err := checkoutPullRequest(clone, "origin", 42, pullRequestHead{Ref: "refs/pull/42/head"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertCheckedOut(t, clone, "pr-42", headHash(t, contrib))
+
+	repo, err := git.PlainOpen(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := repo.Branch("pr-42"); err == nil {
+		t.Errorf("Expected pr-42 to track nothing, got %s %s", b.Remote, b.Merge)
+	}
+}
+
+func TestCheckoutPullRequestErrors(t *testing.T) {
+	contrib, clone := setupPullRequestRemote(t)
+	head := pullRequestHead{
+		Ref:    "refs/pull/42/head",
+		Branch: "feature",
+		Remote: "origin",
+	}
+
+	// unknown pull request
+	if _, err := checkoutPullRequest(clone, "origin", 23, pullRequestHead{Ref: "refs/pull/23/head"}, nil); err == nil {
+		t.Error("Expected an error for an unknown pull request")
+	}
+
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// uncommitted changes
+	writeFile(t, filepath.Join(clone, "README.md"), "changed")
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err == nil {
+		t.Error("Expected an error for a dirty worktree")
+	}
+
+	// diverged from the pull request
+	commitFile(t, clone, "README.md", "local")
+	commitFile(t, contrib, "feature.txt", "force-pushed")
+	push(t, contrib, "refs/heads/feature:refs/pull/42/head")
+	if _, err := checkoutPullRequest(clone, "origin", 42, head, nil); err == nil {
+		t.Error("Expected an error for a diverged branch")
+	}
+}
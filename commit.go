@@ -2,36 +2,41 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 	"github.com/muesli/gitty/vcs"
-	"github.com/muesli/reflow/truncate"
 )
 
-func printCommit(commit vcs.Commit) {
+func printCommit(w io.Writer, commit vcs.Commit, l tableLayout, changed bool) {
 	genericStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGray))
 	numberStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorBlue))
 	timeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorGreen)).Width(8).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(80 - 7)
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
 
 	var s string
-	s += numberStyle.Render(commit.ID[:7])
+	s += renderMarker(l, changed)
+	s += numberStyle.Render(shortSHA(commit.ID))
 	s += genericStyle.Render(" ")
-	s += titleStyle.Render(truncate.StringWithTail(commit.MessageHeadline, 80-7, "…"))
-	s += genericStyle.Render(" ")
-	s += timeStyle.Render(ago(commit.CommittedAt))
-	s += genericStyle.Render(" ")
-	s += numberStyle.Render(commit.Author)
+	s += titleStyle.Render(truncateString(commit.MessageHeadline, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(commit.CommittedAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += numberStyle.Render(truncateString(commit.Author, l.extraWidth))
+	}
 
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 }
 
-func printCommits(repo vcs.Repo) {
+func printCommits(w io.Writer, repo vcs.Repo, hl *changeSet) {
 	commits := repo.LastRelease.CommitsSince
 
 	// dimColor := gamut.ToHex(gamut.Darker(gamut.Hex(theme.colorMagenta), 0.40))
@@ -45,13 +50,13 @@ func printCommits(repo vcs.Repo) {
 		sinceTag = "creation"
 	}
 
-	fmt.Printf("\n🔥 %s %s\n",
+	fmt.Fprintf(w, "\n🔥 %s %s\n",
 		headerStyle.Render(fmt.Sprintf("%s %s",
-			pluralize(len(commits), "commit since", "commits since"),
+			pluralize(repo.LastRelease.CommitCount, "commit since", "commits since"),
 			sinceTag)),
 
 		headerStyle.Render(fmt.Sprintf("(%s)",
-			humanize.Time(repo.LastRelease.PublishedAt))),
+			relTime(repo.LastRelease.PublishedAt))),
 	)
 
 	// trimmed := false
@@ -60,10 +65,66 @@ func printCommits(repo vcs.Repo) {
 		// trimmed = true
 	}
 
+	l := commitsLayout(commits).withMarker(hl != nil)
 	for _, v := range commits {
-		printCommit(v)
+		printCommit(w, v, l, hl.commit(v.ID))
 	}
 	// if trimmed {
 	// 	fmt.Println("...")
 	// }
 }
+
+// commitsLayout returns the table layout for a list of commits.
+func commitsLayout(commits []vcs.Commit) tableLayout {
+	// detect max width of author
+	var authorWidth int
+	for _, v := range commits {
+		if len(v.Author) > authorWidth {
+			authorWidth = len(v.Author)
+		}
+	}
+
+	return newTableLayout(7, false, authorWidth)
+}
+
+// commitsSinceRelease retrieves the latest max commits since the last release
+// of a repository, along with the total amount of commits since. It compares
+// the release tag to the default branch, and falls back to the history since
+// the release date if the tag can't be compared.
+func commitsSinceRelease(client Client, repo vcs.Repo, max int) ([]vcs.Commit, int, error) {
+	if repo.LastRelease.TagName == "" {
+		commits, err := client.History(repo, max, time.Time{})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// everything since the creation of the repository
+		total := repo.Commits
+		if total < len(commits) {
+			total = len(commits)
+		}
+		if max > 0 && len(commits) > max {
+			commits = commits[:max]
+		}
+		return commits, total, nil
+	}
+
+	if repo.DefaultBranch != "" {
+		commits, total, err := client.CommitsSince(repo, max)
+		if err == nil {
+			return commits, total, nil
+		}
+		debugf("can't compare %s to %s of %s: %v", repo.LastRelease.TagName, repo.DefaultBranch, repo.NameWithOwner, err)
+	}
+
+	// the whole history since the release is needed to count its commits
+	commits, err := client.History(repo, 0, repo.LastRelease.PublishedAt)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(commits)
+	if max > 0 && len(commits) > max {
+		commits = commits[:max]
+	}
+	return commits, total, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// compareClient answers commit comparisons and history lookups locally.
+type compareClient struct {
+	Client
+	compared   []vcs.Commit
+	total      int
+	compareErr error
+	history    []vcs.Commit
+}
+
+func (c compareClient) CommitsSince(repo vcs.Repo, max int) ([]vcs.Commit, int, error) {
+	return c.compared, c.total, c.compareErr
+}
+
+func (c compareClient) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit, error) {
+	if max > 0 && len(c.history) > max {
+		return c.history[:max], nil
+	}
+	return c.history, nil
+}
+
+func TestCommitsSinceRelease(t *testing.T) {
+	commits := testCommits()
+	repo := vcs.Repo{
+		DefaultBranch: "master",
+		Commits:       250,
+		LastRelease:   vcs.Release{TagName: "v0.7.0"},
+	}
+
+	// the total comes from comparing the tag, even if only a few commits
+	// were retrieved
+	client := compareClient{compared: commits[:1], total: 120, history: commits}
+	c, total, err := commitsSinceRelease(client, repo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c) != 1 || total != 120 {
+		t.Errorf("Expected 1 of 120 commits, got %d of %d", len(c), total)
+	}
+
+	// fall back to the history if the tag can't be compared, which still
+	// counts all commits since the release
+	client.compareErr = errors.New("tag not found")
+	c, total, err = commitsSinceRelease(client, repo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c) != 1 || total != len(commits) {
+		t.Errorf("Expected 1 of %d commits, got %d of %d", len(commits), len(c), total)
+	}
+
+	// without a release, all commits of the default branch count
+	repo.LastRelease = vcs.Release{}
+	c, total, err = commitsSinceRelease(client, repo, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c) != len(commits) || total != 250 {
+		t.Errorf("Expected %d of 250 commits, got %d of %d", len(commits), len(c), total)
+	}
+}
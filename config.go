@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the settings read from gitty's config file.
+type Config struct {
+	Theme       string                 `json:"theme"`
+	Themes      map[string]ThemeConfig `json:"themes"`
+	Credentials []string               `json:"credentials"`
+
+	// Providers maps hosts to their git provider: github, gitlab or gitea
+	Providers map[string]string `json:"providers"`
+}
+
+var config Config
+
+// configPath returns the location of the config file. It can be overridden
+// with the GITTY_CONFIG env var.
+func configPath() (string, error) {
+	if p := os.Getenv("GITTY_CONFIG"); p != "" {
+		return p, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitty", "config.json"), nil
+}
+
+// loadConfig reads the config file, if there is one.
+func loadConfig() error {
+	path, err := configPath()
+	if err != nil {
+		// no config dir available, stick to the defaults
+		return nil //nolint:nilerr
+	}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, &config); err != nil {
+		return fmt.Errorf("can't parse config file %s: %v", path, err)
+	}
+	return nil
+}
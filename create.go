@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// draftSeparator separates the fields of a draft from its description.
+const draftSeparator = "---"
+
+// draft is an issue or pull request being written in the editor. Head and Base
+// are only used for pull requests.
+type draft struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+	Milestone string
+	Head      string
+	Base      string
+
+	pullRequest bool
+}
+
+// template returns the text the draft gets edited as.
+func (d draft) template(repo string) string {
+	var s strings.Builder
+	if d.pullRequest {
+		fmt.Fprintf(&s, "# Creating a pull request in %s.\n", repo)
+	} else {
+		fmt.Fprintf(&s, "# Creating an issue in %s.\n", repo)
+	}
+	fmt.Fprintf(&s, "# Lines starting with '#' are ignored. Separate labels and assignees with\n")
+	fmt.Fprintf(&s, "# commas. Everything below the %s line is the description. Leave the title\n", draftSeparator)
+	fmt.Fprintf(&s, "# empty to abort.\n")
+
+	fmt.Fprintf(&s, "Title: %s\n", d.Title)
+	fmt.Fprintf(&s, "Labels: %s\n", strings.Join(d.Labels, ", "))
+	fmt.Fprintf(&s, "Assignees: %s\n", strings.Join(d.Assignees, ", "))
+	fmt.Fprintf(&s, "Milestone: %s\n", d.Milestone)
+	if d.pullRequest {
+		fmt.Fprintf(&s, "Base: %s\n", d.Base)
+		fmt.Fprintf(&s, "Head: %s\n", d.Head)
+	}
+	fmt.Fprintf(&s, "%s\n", draftSeparator)
+	fmt.Fprintf(&s, "%s\n", d.Body)
+
+	return s.String()
+}
+
+// splitList splits a comma separated list, dropping empty elements.
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// parseDraft parses a draft edited by the user.
+func parseDraft(s string, pullRequest bool) (draft, error) {
+	d := draft{pullRequest: pullRequest}
+
+	var body []string
+	var inBody bool
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inBody {
+			body = append(body, line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == draftSeparator {
+			inBody = true
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return d, fmt.Errorf("invalid line: %s", line)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch strings.ToLower(key) {
+		case "title":
+			d.Title = value
+		case "labels":
+			d.Labels = splitList(value)
+		case "assignees":
+			d.Assignees = splitList(value)
+		case "milestone":
+			d.Milestone = value
+		case "base":
+			d.Base = value
+		case "head":
+			d.Head = value
+		default:
+			return d, fmt.Errorf("unknown field: %s", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return d, err
+	}
+
+	d.Body = strings.TrimSpace(strings.Join(body, "\n"))
+	if d.Title == "" {
+		return d, errors.New("aborting due to an empty title")
+	}
+	if pullRequest && (d.Base == "" || d.Head == "") {
+		return d, errors.New("base and head branch are required")
+	}
+	return d, nil
+}
+
+// editor returns the command to edit files with.
+func editor() []string {
+	if e := strings.Fields(os.Getenv("EDITOR")); len(e) > 0 {
+		return e
+	}
+	return []string{"vi"}
+}
+
+// editDraft lets the user edit a draft in their editor.
+func editDraft(d draft, repo string) (draft, error) {
+	f, err := os.CreateTemp("", "gitty-*.md")
+	if err != nil {
+		return d, fmt.Errorf("can't create draft: %v", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	if _, err := f.WriteString(d.template(repo)); err != nil {
+		return d, fmt.Errorf("can't write draft: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return d, fmt.Errorf("can't write draft: %v", err)
+	}
+
+	e := editor()
+	cmd := exec.Command(e[0], append(e[1:], f.Name())...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return d, fmt.Errorf("can't run editor: %v", err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return d, fmt.Errorf("can't read draft: %v", err)
+	}
+	nd, err := parseDraft(string(b), d.pullRequest)
+	if err != nil {
+		// keep the draft around, so nothing gets lost
+		return nd, fmt.Errorf("%v (your draft was saved to %s)", err, f.Name())
+	}
+
+	_ = os.Remove(f.Name())
+	return nd, nil
+}
+
+func parseCreate(kind string, args []string) {
+	pullRequest := kind == "pr"
+	usage := "Usage: gitty issue create [FLAGS]"
+	if pullRequest {
+		usage = "Usage: gitty pr create [FLAGS]"
+	}
+
+	fs := flag.NewFlagSet(kind+" create", flag.ExitOnError)
+	title := fs.String("title", "", "Title")
+	body := fs.String("body", "", "Description")
+	labels := fs.String("labels", "", "Comma separated labels")
+	assignees := fs.String("assignees", "", "Comma separated assignees")
+	milestone := fs.String("milestone", "", "Milestone")
+	var base, head *string
+	if pullRequest {
+		base = fs.String("base", "", "Branch to merge into (defaults to the default branch)")
+		head = fs.String("head", "", "Branch to merge (defaults to the current branch)")
+	}
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 || args[0] != "create" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	_ = fs.Parse(args[1:])
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	host, owner, name, _, err := parseRepo(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	client, err := guessClient(host)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	d := draft{
+		Title:       *title,
+		Body:        *body,
+		Labels:      splitList(*labels),
+		Assignees:   splitList(*assignees),
+		Milestone:   *milestone,
+		pullRequest: pullRequest,
+	}
+	if pullRequest {
+		d.Base, d.Head = *base, *head
+		if d.Head == "" {
+			if d.Head, err = pushedBranch("."); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		if d.Base == "" {
+			repo, err := client.Repository(owner, name)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			d.Base = repo.DefaultBranch
+		}
+	}
+
+	d, err = editDraft(d, owner+"/"+name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if !pullRequest {
+		issue, err := client.CreateIssue(owner, name, vcs.NewIssue{
+			Title:     d.Title,
+			Body:      d.Body,
+			Labels:    d.Labels,
+			Assignees: d.Assignees,
+			Milestone: d.Milestone,
+		})
+		if err != nil {
+			fmt.Printf("can't create issue: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created issue #%d: %s\n", issue.ID, issue.URL)
+		return
+	}
+
+	pr, err := client.CreatePullRequest(owner, name, vcs.NewPullRequest{
+		Title:     d.Title,
+		Body:      d.Body,
+		Head:      d.Head,
+		Base:      d.Base,
+		Labels:    d.Labels,
+		Assignees: d.Assignees,
+		Milestone: d.Milestone,
+	})
+	if err != nil && pr == nil {
+		fmt.Printf("can't create pull request: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Created pull request #%d: %s\n", pr.ID, pr.URL)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
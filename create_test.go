@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDraftTemplate(t *testing.T) {
+	d := draft{
+		Title:       "Add gitty pr create",
+		Body:        "Lets you open pull requests\nfrom the command-line.",
+		Labels:      []string{"enhancement", "cli"},
+		Assignees:   []string{"muesli"},
+		Milestone:   "v1.0.0",
+		Head:        "feature/create",
+		Base:        "master",
+		pullRequest: true,
+	}
+
+	parsed, err := parseDraft(d.template("muesli/gitty"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, d) {
+		t.Errorf("Expected %+v, got %+v", d, parsed)
+	}
+}
+
+func TestParseDraft(t *testing.T) {
+	s := `# a comment
+Title:  Crash on start
+labels: bug, , crash
+Assignees:
+---
+# Steps to reproduce
+
+Run gitty.
+`
+	d, err := parseDraft(s, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Title != "Crash on start" {
+		t.Errorf("Expected title %q, got %q", "Crash on start", d.Title)
+	}
+	if exp := []string{"bug", "crash"}; !reflect.DeepEqual(d.Labels, exp) {
+		t.Errorf("Expected labels %v, got %v", exp, d.Labels)
+	}
+	if d.Assignees != nil {
+		t.Errorf("Expected no assignees, got %v", d.Assignees)
+	}
+	// headings in the description are kept
+	if exp := "# Steps to reproduce\n\nRun gitty."; d.Body != exp {
+		t.Errorf("Expected body %q, got %q", exp, d.Body)
+	}
+}
+
+func TestParseDraftErrors(t *testing.T) {
+	for _, s := range []string{
+		"Title:\n---\nbody",
+		"Title: Crash\nPriority: high\n---\n",
+		"Title: Crash\nnot a field\n---\n",
+	} {
+		if _, err := parseDraft(s, false); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+
+	if _, err := parseDraft("Title: Feature\nHead: feature\n---\n", true); err == nil {
+		t.Error("Expected an error for a pull request without a base branch")
+	}
+}
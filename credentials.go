@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// credentialSource looks up the token for a host. It returns an empty string
+// if it doesn't know the host.
+type credentialSource func(host string) string
+
+// credentialSources are all the places gitty can find tokens in.
+var credentialSources = map[string]credentialSource{
+	"env":            envToken,
+	"gh":             ghToken,
+	"glab":           glabToken,
+	"tea":            teaToken,
+	"netrc":          netrcToken,
+	"netrc-default":  netrcDefaultToken,
+	"git-credential": gitCredentialToken,
+}
+
+// defaultCredentialOrder is the order credential sources get tried in, unless
+// configured otherwise. Asking git's credential helpers comes last, as it's
+// the slowest. The netrc default entry is left out, as it would be sent to
+// any host.
+var defaultCredentialOrder = []string{"env", "gh", "glab", "tea", "netrc", "git-credential"}
+
+var (
+	tokenMut   sync.Mutex
+	tokenCache = map[string]string{}
+)
+
+// validateCredentialOrder returns an error if the configured credential order
+// contains an unknown source.
+func validateCredentialOrder() error {
+	for _, name := range config.Credentials {
+		if _, ok := credentialSources[name]; !ok {
+			return fmt.Errorf("unknown credential source: %s", name)
+		}
+	}
+	return nil
+}
+
+// tokenForHost returns the token for a host from the first credential source
+// that knows it.
+func tokenForHost(host string) string {
+	tokenMut.Lock()
+	defer tokenMut.Unlock()
+
+	host = strings.ToLower(host)
+	if token, ok := tokenCache[host]; ok {
+		return token
+	}
+
+	order := defaultCredentialOrder
+	if len(config.Credentials) > 0 {
+		order = config.Credentials
+	}
+
+	var token string
+	for _, name := range order {
+		if src, ok := credentialSources[name]; ok {
+			if token = src(host); token != "" {
+				break
+			}
+		}
+	}
+
+	tokenCache[host] = token
+	return token
+}
+
+// envToken reads the token from the GITTY_TOKENS env var.
+func envToken(host string) string {
+	token := os.Getenv("GITTY_TOKENS")
+
+	tokens := strings.Split(token, ";")
+	for _, t := range tokens {
+		if !strings.Contains(t, "=") {
+			continue
+		}
+
+		s := strings.Split(t, "=")
+		k, v := s[0], s[1]
+		if !strings.EqualFold(strings.TrimSpace(k), host) {
+			continue
+		}
+
+		return strings.TrimSpace(v)
+	}
+
+	// fallback for old tokens
+	if host == "github.com" {
+		token = os.Getenv("GITTY_TOKEN")
+		if len(token) > 0 {
+			return token
+		}
+		token = os.Getenv("GITHUB_TOKEN")
+		if len(token) > 0 {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// xdgConfigDir returns the config dir most CLI tools use, even on macOS.
+func xdgConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// readYAML decodes a YAML file into v. It reports false if the file doesn't
+// exist or can't be parsed.
+func readYAML(path string, v interface{}) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return yaml.Unmarshal(b, v) == nil
+}
+
+// ghToken reads the token from the config of GitHub's gh CLI.
+func ghToken(host string) string {
+	dir := os.Getenv("GH_CONFIG_DIR")
+	if dir == "" {
+		dir = filepath.Join(xdgConfigDir(), "gh")
+		if runtime.GOOS == "windows" && os.Getenv("XDG_CONFIG_HOME") == "" {
+			dir = filepath.Join(os.Getenv("AppData"), "GitHub CLI")
+		}
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if !readYAML(filepath.Join(dir, "hosts.yml"), &hosts) {
+		return ""
+	}
+	for k, v := range hosts {
+		if strings.EqualFold(k, host) {
+			return v.OAuthToken
+		}
+	}
+	return ""
+}
+
+// glabToken reads the token from the config of GitLab's glab CLI.
+func glabToken(host string) string {
+	dir := os.Getenv("GLAB_CONFIG_DIR")
+	if dir == "" {
+		dir = filepath.Join(xdgConfigDir(), "glab-cli")
+	}
+
+	var cfg struct {
+		Hosts map[string]struct {
+			Token string `yaml:"token"`
+		} `yaml:"hosts"`
+	}
+	if !readYAML(filepath.Join(dir, "config.yml"), &cfg) {
+		return ""
+	}
+	for k, v := range cfg.Hosts {
+		if strings.EqualFold(k, host) {
+			return v.Token
+		}
+	}
+	return ""
+}
+
+// teaToken reads the token from the config of Gitea's tea CLI.
+func teaToken(host string) string {
+	dirs := []string{filepath.Join(xdgConfigDir(), "tea")}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "tea"))
+	}
+
+	for _, dir := range dirs {
+		var cfg struct {
+			Logins []struct {
+				URL   string `yaml:"url"`
+				Token string `yaml:"token"`
+			} `yaml:"logins"`
+		}
+		if !readYAML(filepath.Join(dir, "config.yml"), &cfg) {
+			continue
+		}
+
+		for _, l := range cfg.Logins {
+			u, err := url.Parse(l.URL)
+			if err == nil && strings.EqualFold(u.Host, host) {
+				return l.Token
+			}
+		}
+	}
+	return ""
+}
+
+// netrcPath returns the location of the user's netrc file.
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(home, ".netrc")
+	if runtime.GOOS == "windows" {
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(home, "_netrc")
+		}
+	}
+	return path
+}
+
+// netrcToken reads the password of a host from the user's netrc file.
+func netrcToken(host string) string {
+	b, err := os.ReadFile(netrcPath())
+	if err != nil {
+		return ""
+	}
+	return parseNetrc(b, host)
+}
+
+// netrcDefaultToken reads the password of the default entry from the user's
+// netrc file. As it gets sent to any host, it has to be enabled explicitly.
+func netrcDefaultToken(host string) string {
+	b, err := os.ReadFile(netrcPath())
+	if err != nil {
+		return ""
+	}
+	for _, e := range parseNetrcEntries(b) {
+		if e.isDefault {
+			return e.password
+		}
+	}
+	return ""
+}
+
+// parseNetrc returns the password of a machine from a netrc file.
+func parseNetrc(b []byte, host string) string {
+	for _, e := range parseNetrcEntries(b) {
+		if !e.isDefault && strings.EqualFold(e.machine, host) && e.password != "" {
+			return e.password
+		}
+	}
+	return ""
+}
+
+// netrcEntry is a machine or the default entry of a netrc file.
+type netrcEntry struct {
+	machine   string
+	isDefault bool
+	password  string
+}
+
+// parseNetrcEntries returns all entries of a netrc file. Macro definitions
+// run until the next empty line and get skipped.
+func parseNetrcEntries(b []byte) []netrcEntry {
+	var entries []netrcEntry
+	var inMacro bool
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if inMacro {
+			inMacro = len(fields) > 0
+			continue
+		}
+
+		for i := 0; i < len(fields); i++ {
+			switch fields[i] {
+			case "machine":
+				entries = append(entries, netrcEntry{})
+				if i+1 < len(fields) {
+					i++
+					entries[len(entries)-1].machine = fields[i]
+				}
+			case "default":
+				entries = append(entries, netrcEntry{isDefault: true})
+			case "password":
+				if i+1 < len(fields) {
+					i++
+					if len(entries) > 0 {
+						entries[len(entries)-1].password = fields[i]
+					}
+				}
+			case "login", "account", "port":
+				// skip the value
+				i++
+			case "macdef":
+				// the rest of the line names the macro
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+
+	return entries
+}
+
+// gitCredentialToken asks git's credential helpers for the password of a host,
+// without ever prompting the user. An empty askpass setting makes git fall
+// back to other ones, so they all point at a command answering nothing.
+func gitCredentialToken(host string) string {
+	cmd := exec.Command("git", "-c", "core.askPass=true", "credential", "fill")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ASKPASS=true",
+		"SSH_ASKPASS=true",
+		"GCM_INTERACTIVE=never",
+	)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return parseCredential(out)
+}
+
+// parseCredential returns the password of git's credential output format.
+func parseCredential(b []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		if v := strings.TrimPrefix(s.Text(), "password="); v != s.Text() {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func resetTokenCache(t *testing.T) {
+	t.Helper()
+
+	tokenCache = map[string]string{}
+	t.Cleanup(func() { tokenCache = map[string]string{} })
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseNetrc(t *testing.T) {
+	netrc := `machine github.com
+	login muesli
+	password gh-token
+
+macdef init
+cd /pub
+machine evil.example.com password macro-token
+
+machine gitlab.com login muesli password gl-token
+default login anonymous password fallback-token
+`
+
+	var tests = []struct {
+		host     string
+		expected string
+	}{
+		{"github.com", "gh-token"},
+		{"GitLab.com", "gl-token"},
+		// the default entry isn't used unless enabled explicitly
+		{"codeberg.org", ""},
+		// macro bodies aren't entries
+		{"evil.example.com", ""},
+	}
+
+	for _, test := range tests {
+		if token := parseNetrc([]byte(netrc), test.host); token != test.expected {
+			t.Errorf("parseNetrc(%s) %s != %s", test.host, token, test.expected)
+		}
+	}
+
+	if token := parseNetrc([]byte("machine github.com login muesli"), "github.com"); token != "" {
+		t.Errorf("Expected no token, got %s", token)
+	}
+}
+
+func TestNetrcDefault(t *testing.T) {
+	resetTokenCache(t)
+
+	dir := t.TempDir()
+	netrc := filepath.Join(dir, "netrc")
+	writeFile(t, netrc, "machine github.com password gh-token\ndefault login anonymous password fallback-token\n")
+	t.Setenv("NETRC", netrc)
+
+	prev := config.Credentials
+	t.Cleanup(func() { config.Credentials = prev })
+
+	config.Credentials = []string{"netrc"}
+	if token := tokenForHost("codeberg.org"); token != "" {
+		t.Errorf("Expected no token without opting in, got %s", token)
+	}
+
+	resetTokenCache(t)
+	config.Credentials = []string{"netrc", "netrc-default"}
+	if token := tokenForHost("codeberg.org"); token != "fallback-token" {
+		t.Errorf("Expected fallback-token, got %s", token)
+	}
+	if token := tokenForHost("github.com"); token != "gh-token" {
+		t.Errorf("Expected gh-token, got %s", token)
+	}
+}
+
+func TestParseCredential(t *testing.T) {
+	out := "protocol=https\nhost=github.com\nusername=muesli\npassword=secret\n"
+	if token := parseCredential([]byte(out)); token != "secret" {
+		t.Errorf("Expected secret, got %s", token)
+	}
+}
+
+func TestCLIConfigTokens(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("GH_CONFIG_DIR", filepath.Join(dir, "gh"))
+	t.Setenv("GLAB_CONFIG_DIR", "")
+
+	writeFile(t, filepath.Join(dir, "gh", "hosts.yml"), `github.com:
+    user: muesli
+    oauth_token: gh-token
+    git_protocol: https
+`)
+	writeFile(t, filepath.Join(dir, "glab-cli", "config.yml"), `git_protocol: ssh
+hosts:
+    gitlab.com:
+        token: gl-token
+        api_host: gitlab.com
+`)
+	writeFile(t, filepath.Join(dir, "tea", "config.yml"), `logins:
+  - name: codeberg
+    url: https://codeberg.org
+    token: tea-token
+`)
+
+	if token := ghToken("github.com"); token != "gh-token" {
+		t.Errorf("Expected gh-token, got %s", token)
+	}
+	if token := glabToken("gitlab.com"); token != "gl-token" {
+		t.Errorf("Expected gl-token, got %s", token)
+	}
+	if token := teaToken("codeberg.org"); token != "tea-token" {
+		t.Errorf("Expected tea-token, got %s", token)
+	}
+	if token := ghToken("gitlab.com"); token != "" {
+		t.Errorf("Expected no token, got %s", token)
+	}
+}
+
+func TestCredentialOrder(t *testing.T) {
+	resetTokenCache(t)
+
+	dir := t.TempDir()
+	netrc := filepath.Join(dir, "netrc")
+	writeFile(t, netrc, "machine github.com password netrc-token\n")
+	t.Setenv("NETRC", netrc)
+	t.Setenv("GITTY_TOKENS", "github.com=env-token")
+
+	prev := config.Credentials
+	t.Cleanup(func() { config.Credentials = prev })
+
+	config.Credentials = nil
+	if token := tokenForHost("github.com"); token != "env-token" {
+		t.Errorf("Expected env-token, got %s", token)
+	}
+
+	resetTokenCache(t)
+	config.Credentials = []string{"netrc", "env"}
+	if token := tokenForHost("github.com"); token != "netrc-token" {
+		t.Errorf("Expected netrc-token, got %s", token)
+	}
+
+	config.Credentials = []string{"netrc", "keychain"}
+	if err := validateCredentialOrder(); err == nil {
+		t.Error("Expected an error for an unknown credential source")
+	}
+}
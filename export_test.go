@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestWriteMarkdownOverview(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	writeMarkdownOverview(&buf, testOverview())
+	assertGolden(t, "overview_markdown", buf.Bytes())
+}
+
+func TestWriteHTMLOverview(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	if err := writeHTMLOverview(&buf, testOverview()); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "overview_html", buf.Bytes())
+}
+
+func TestWriteReleases(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	o := testOverview()
+	stale := o.Repo
+	stale.Name = "stale"
+	stale.LastRelease.CommitsSince = nil
+	stale.LastRelease.CommitCount = 0
+	repos := []vcs.Repo{o.Repo, stale}
+
+	var buf bytes.Buffer
+	writeMarkdownReleases(&buf, repos, false)
+	assertGolden(t, "releases_markdown", buf.Bytes())
+
+	buf.Reset()
+	if err := writeHTMLReleases(&buf, repos, false); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "releases_html", buf.Bytes())
+}
+
+func TestWriteReleasesWithHost(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	repo := testOverview().Repo
+	repo.Host = "github.com"
+	fork := repo
+	fork.Host = "codeberg.org"
+	fork.URL = "https://codeberg.org/muesli/gitty"
+	fork.LastRelease.URL = "https://codeberg.org/muesli/gitty/releases/tag/v0.7.0"
+	repos := []vcs.Repo{repo, fork}
+
+	var buf bytes.Buffer
+	writeMarkdownReleases(&buf, repos, true)
+	assertGolden(t, "releases_hosts_markdown", buf.Bytes())
+
+	buf.Reset()
+	if err := writeHTMLReleases(&buf, repos, true); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "releases_hosts_html", buf.Bytes())
+}
@@ -23,58 +23,85 @@ const (
 
 // Client defines the set of methods required from a git provider.
 type Client interface {
-	Issues(owner string, name string) ([]vcs.Issue, error)
-	PullRequests(owner string, name string) ([]vcs.PullRequest, error)
+	Issues(owner string, name string, opts vcs.ListOptions) ([]vcs.Issue, int, error)
+	Issue(owner string, name string, number int) (*vcs.Issue, error)
+	IssueComments(owner string, name string, number int) ([]vcs.Comment, error)
+	PullRequests(owner string, name string, opts vcs.ListOptions) ([]vcs.PullRequest, int, error)
+	PullRequest(owner string, name string, number int) (*vcs.PullRequestStatus, error)
+	PullRequestForBranch(owner string, name string, branch string, headOwners []string) (*vcs.PullRequestStatus, error)
+	PullRequestComments(owner string, name string, number int) ([]vcs.Comment, error)
 	Repository(owner string, name string) (vcs.Repo, error)
 	Repositories(owner string) ([]vcs.Repo, error)
-	Branches(owner string, name string) ([]vcs.Branch, error)
+	Branches(owner string, name string, opts vcs.ListOptions) ([]vcs.Branch, error)
 	History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit, error)
+	CommitsSince(repo vcs.Repo, max int) ([]vcs.Commit, int, error)
+	Milestones(owner string, name string) ([]vcs.Milestone, error)
+	Pipelines(owner string, name string, branches []string) ([]vcs.Pipeline, error)
+	CreateIssue(owner string, name string, issue vcs.NewIssue) (*vcs.Issue, error)
+	CreatePullRequest(owner string, name string, pr vcs.NewPullRequest) (*vcs.PullRequest, error)
 
 	GetUsername() (string, error)
+	Dashboard(username string) (vcs.Dashboard, error)
+	Notifications() ([]vcs.Notification, error)
+	MarkNotificationRead(id string) error
+	MarkAllNotificationsRead() error
+	RateLimit() (vcs.RateLimit, error)
 	IssueURL(owner string, name string, number int) string
+	RepositoryURL(owner string, name string) string
+	BranchURL(owner string, name string, branch string) string
+	CommitURL(owner string, name string, sha string) string
+	ReleaseURL(owner string, name string, tag string) string
+	CompareURL(owner string, name string, base string, head string) string
+	PipelinesURL(owner string, name string, branch string) string
+	NewPullRequestURL(owner string, name string, base string, head string) string
+	PullRequestRef(number int) string
 }
 
-func tokenForHost(host string) string {
-	token := os.Getenv("GITTY_TOKENS")
-
-	tokens := strings.Split(token, ";")
-	for _, t := range tokens {
+// configuredHosts returns all hosts we have a token for.
+func configuredHosts() []string {
+	var hosts []string
+	for _, t := range strings.Split(os.Getenv("GITTY_TOKENS"), ";") {
 		if !strings.Contains(t, "=") {
 			continue
 		}
 
-		s := strings.Split(t, "=")
-		k, v := s[0], s[1]
-		if !strings.EqualFold(k, host) {
-			continue
-		}
-
-		return strings.TrimSpace(v)
+		hosts = append(hosts, strings.TrimSpace(strings.Split(t, "=")[0]))
 	}
 
-	// fallback for old tokens
-	if host == "github.com" {
-		token = os.Getenv("GITTY_TOKEN")
-		if len(token) > 0 {
-			return token
+	if tokenForHost("github.com") != "" {
+		var found bool
+		for _, h := range hosts {
+			found = found || strings.EqualFold(h, "github.com")
 		}
-		token = os.Getenv("GITHUB_TOKEN")
-		if len(token) > 0 {
-			return token
+		if !found {
+			hosts = append(hosts, "github.com")
 		}
 	}
 
-	return ""
+	return hosts
 }
 
 func guessClient(host string) (Client, error) {
 	token := tokenForHost(host)
 	if len(token) == 0 {
-		return nil, fmt.Errorf("please set a GITTY_TOKENS env var for host " + host)
+		return nil, fmt.Errorf("no credentials found for host %s, please set a GITTY_TOKENS env var", host)
+	}
+
+	switch p := providerForHost(host); p {
+	case "github":
+		return github.NewClient(host, token, true)
+	case "gitlab":
+		return gitlab.NewClient(host, token, true)
+	case "gitea":
+		return gitea.NewClient(host, token, true)
+	case "":
+		// not configured, guess below
+	default:
+		return nil, fmt.Errorf("unknown provider %s for host %s", p, host)
 	}
 
 	if strings.EqualFold(host, "github.com") {
-		return github.NewClient(token)
+		return github.NewClient(host, token, true)
 	}
 	if strings.EqualFold(host, "gitlab.com") {
 		return gitlab.NewClient(host, token, true)
@@ -99,11 +126,25 @@ func guessClient(host string) (Client, error) {
 	if err == nil {
 		return client, nil
 	}
+	client, err = github.NewClient(host, token, false)
+	if err == nil {
+		return client, nil
+	}
 	// fmt.Println(err)
 
 	return nil, fmt.Errorf("not a recognized git provider")
 }
 
+// providerForHost returns the git provider configured for a host, if any.
+func providerForHost(host string) string {
+	for k, v := range config.Providers {
+		if strings.EqualFold(k, host) {
+			return strings.ToLower(v)
+		}
+	}
+	return ""
+}
+
 // remoteURL returns remote name and URL
 func remoteURL(path string) (string, string, error) {
 	r, err := git.PlainOpen(path)
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/muesli/gitty/vcs/github"
+)
 
 func TestCleanupURL(t *testing.T) {
 	var tests = []struct {
@@ -24,3 +28,57 @@ func TestCleanupURL(t *testing.T) {
 		}
 	}
 }
+
+func TestParseProjectSources(t *testing.T) {
+	prev := *namespace
+	*namespace = "muesli"
+	defer func() { *namespace = prev }()
+
+	sources := parseProjectSources([]string{
+		"github.com",
+		"gitlab.com:ourgroup",
+		"codeberg.org:",
+		"git.example.com:8443",
+		"git.example.com:8443:",
+		"git.example.com:8443:group",
+	})
+	expected := []projectSource{
+		{host: "github.com", namespace: "muesli"},
+		{host: "gitlab.com", namespace: "ourgroup"},
+		{host: "codeberg.org", namespace: "muesli"},
+		{host: "git.example.com:8443", namespace: "muesli"},
+		{host: "git.example.com:8443", namespace: "muesli"},
+		{host: "git.example.com:8443", namespace: "group"},
+	}
+
+	if len(sources) != len(expected) {
+		t.Fatalf("Expected %d sources, got %d", len(expected), len(sources))
+	}
+	for i, src := range sources {
+		if src != expected[i] {
+			t.Errorf("parseProjectSources: %+v != %+v", src, expected[i])
+		}
+	}
+}
+
+func TestConfiguredProvider(t *testing.T) {
+	t.Setenv("GITTY_TOKENS", "github.example.com=token;git.example.com=token")
+	prev := config.Providers
+	config.Providers = map[string]string{
+		"GitHub.example.com": "GitHub",
+		"git.example.com":    "svn",
+	}
+	defer func() { config.Providers = prev }()
+
+	client, err := guessClient("github.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.(*github.Client); !ok {
+		t.Errorf("Expected a GitHub client, got %T", client)
+	}
+
+	if _, err := guessClient("git.example.com"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; color: #24292f; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: 0.2em 0.5em; vertical-align: top; }
+td.num, td.age { text-align: right; white-space: nowrap; }
+td.age, .meta { color: #57606a; }
+code { font-size: 0.9em; }
+.label { display: inline-block; padding: 0 0.5em; margin-right: 0.2em; border: 1px solid; border-radius: 1em; font-size: 0.85em; }
+</style>
+</head>
+<body>
+`
+
+const htmlFooter = `</body>
+</html>
+`
+
+const htmlTemplates = `
+{{define "labels"}}{{range .}}<span class="label" style="color: {{.Color}}; border-color: {{.Color}}">{{.Name}}</span>{{end}}{{end}}
+
+{{define "link"}}{{if .URL}}<a href="{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}
+
+{{define "commits"}}<ul>
+{{range .}}<li><code>{{template "link" (link (sha .ID) .URL)}}</code> {{.MessageHeadline}} <span class="meta">({{.Author}}, {{ago .CommittedAt}})</span></li>
+{{end}}</ul>
+{{end}}
+
+{{define "overview"}}` + htmlHeader + `<h1>{{template "link" (link (printf "%s/%s" .Owner .Name) .URL)}}</h1>
+{{with .BranchPullRequest}}
+<h2>🔀 Pull request for {{.Branch}}</h2>
+<p>{{template "link" (link (printf "#%d" .ID) .URL)}} {{.Title}}</p>
+<p class="meta">{{prStatus .}}</p>
+{{end}}
+{{if .Pipelines}}
+<h2>🚦 {{pluralize (len .Pipelines) "pipeline" "pipelines"}}</h2>
+<table>
+{{range .Pipelines}}<tr><td>{{.Branch}}</td><td>{{.Status}}</td><td>{{template "link" (link .Name .URL)}}{{if and .FailedJob (ne .FailedJob .Name)}}: {{.FailedJob}}{{end}}</td><td class="age">{{duration .}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>🐛 {{pluralizeTotal .IssueCount (len .Issues) "open issue" "open issues"}}</h2>
+<table>
+{{range head maxIssues .Issues}}<tr><td class="num">{{template "link" (link (printf "#%d" .ID) .URL)}}</td><td>{{.Title}}</td><td class="age">{{ago .CreatedAt}}</td><td>{{template "labels" .Labels}}</td></tr>
+{{end}}</table>
+
+<h2>📌 {{pluralizeTotal .PullRequestCount (len .PullRequests) "open pull request" "open pull requests"}}</h2>
+<table>
+{{range head maxPullRequests .PullRequests}}<tr><td class="num">{{template "link" (link (printf "#%d" .ID) .URL)}}</td><td>{{.Title}}</td><td class="age">{{ago .CreatedAt}}</td><td>{{template "labels" .Labels}}</td></tr>
+{{end}}</table>
+
+{{if .Milestones}}
+<h2>🎯 {{pluralize (len .Milestones) "open milestone" "open milestones"}}</h2>
+<table>
+{{range .Milestones}}<tr><td>{{template "link" (link .Title .URL)}}</td><td class="num"><progress max="1" value="{{.Progress}}"></progress> {{percent .}}</td><td class="age">{{due .}}</td><td class="num">{{.ClosedIssues}}/{{total .}}</td></tr>
+{{end}}</table>
+{{end}}
+{{$branches := head maxBranches .Branches}}
+<h2>🌳 {{pluralize (len $branches) "active branch" "active branches"}}</h2>
+<table>
+{{range $branches}}<tr><td>{{template "link" (link .Name .URL)}}</td><td>{{template "link" (link .LastCommit.MessageHeadline .LastCommit.URL)}}</td><td class="age">{{ago .LastCommit.CommittedAt}}</td><td>{{.LastCommit.Author}}</td></tr>
+{{end}}</table>
+
+<h2>🔥 {{pluralize .Repo.LastRelease.CommitCount "commit since" "commits since"}} {{if .Repo.LastRelease.TagName}}{{template "link" (link .Repo.LastRelease.TagName .Repo.LastRelease.URL)}}{{else}}creation{{end}} <span class="meta">({{since .Repo.LastRelease.PublishedAt}})</span></h2>
+{{template "commits" (head maxCommits .Commits)}}` + htmlFooter + `{{end}}
+
+{{define "releases"}}` + htmlHeader + `<h1>{{.Title}}</h1>
+<ul>
+{{range .Repos}}<li>{{if $.WithHost}}<span class="meta">{{.Host}}</span> {{end}}<strong>{{template "link" (link .Name .URL)}}</strong> {{template "link" (link .LastRelease.TagName .LastRelease.URL)}} <span class="meta">({{since .LastRelease.PublishedAt}}, {{.LastRelease.CommitCount}} new commits since)</span>
+{{if withCommits}}{{template "commits" (head maxCommits .LastRelease.CommitsSince)}}{{end}}</li>
+{{end}}</ul>
+` + htmlFooter + `{{end}}
+`
+
+type htmlLink struct {
+	Text string
+	URL  string
+}
+
+var htmlTmpl = template.Must(template.New("html").Funcs(template.FuncMap{
+	"ago":            ago,
+	"since":          relTime,
+	"pluralize":      pluralize,
+	"pluralizeTotal": pluralizeTotal,
+	"head":           head,
+	"sha":            shortSHA,
+	"duration":       pipelineDuration,
+	"due":            due,
+	"prStatus":       pullRequestStatusText,
+	"percent": func(m vcs.Milestone) string {
+		return fmt.Sprintf("%d%%", int(m.Progress()*100))
+	},
+	"total": func(m vcs.Milestone) int {
+		return m.OpenIssues + m.ClosedIssues
+	},
+	"link": func(text, u string) htmlLink {
+		return htmlLink{Text: text, URL: u}
+	},
+	"maxIssues":       func() int { return *maxIssues },
+	"maxPullRequests": func() int { return *maxPullRequests },
+	"maxBranches":     func() int { return *maxBranches },
+	"maxCommits":      func() int { return *maxCommits },
+	"withCommits":     func() bool { return *withCommits },
+}).Parse(htmlTemplates))
+
+func writeHTMLOverview(w io.Writer, o *Overview) error {
+	return htmlTmpl.ExecuteTemplate(w, "overview", struct {
+		*Overview
+		Title string
+	}{o, o.Owner + "/" + o.Name})
+}
+
+// writeHTMLReleases writes the release report as HTML. withHost adds the host
+// to every repository, to tell apart repositories with the same name.
+func writeHTMLReleases(w io.Writer, repos []vcs.Repo, withHost bool) error {
+	var rr []vcs.Repo
+	for _, repo := range repos {
+		if *skipStaleRepos && repo.LastRelease.CommitCount < *minNewCommits {
+			continue
+		}
+		rr = append(rr, repo)
+	}
+
+	return htmlTmpl.ExecuteTemplate(w, "releases", struct {
+		Repos    []vcs.Repo
+		Title    string
+		WithHost bool
+	}{rr, pluralize(len(rr), "repository with a release", "repositories with a release"), withHost})
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+const inboxUsage = `Usage: gitty inbox [HOST...]
+       gitty inbox done HOST ID...
+       gitty inbox done --all HOST
+`
+
+// hostInbox is the unread notifications of the token owner on a host.
+type hostInbox struct {
+	Host          string
+	Notifications []vcs.Notification
+	Err           error
+}
+
+// notificationGroup is the notifications of a single repository.
+type notificationGroup struct {
+	Repo          string
+	Notifications []vcs.Notification
+}
+
+// groupNotifications groups notifications by their repository. Repositories
+// are sorted by name, their notifications by the latest activity.
+func groupNotifications(notifications []vcs.Notification) []notificationGroup {
+	idx := map[string]int{}
+	var groups []notificationGroup
+	for _, n := range notifications {
+		i, ok := idx[n.Repo]
+		if !ok {
+			i = len(groups)
+			idx[n.Repo] = i
+			groups = append(groups, notificationGroup{Repo: n.Repo})
+		}
+		groups[i].Notifications = append(groups[i].Notifications, n)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Repo < groups[j].Repo
+	})
+	for _, g := range groups {
+		nn := g.Notifications
+		sort.SliceStable(nn, func(i, j int) bool {
+			return nn[i].UpdatedAt.After(nn[j].UpdatedAt)
+		})
+	}
+	return groups
+}
+
+// fetchInboxes retrieves the notifications of the token owners on all given
+// hosts.
+func fetchInboxes(hosts []string) []hostInbox {
+	inboxes := make([]hostInbox, len(hosts))
+	pool := newWorkPool(*concurrency)
+	wg := &sync.WaitGroup{}
+	for i, host := range hosts {
+		i, host := i, host
+		pool.Go(wg, func() {
+			inboxes[i].Host = host
+
+			client, err := guessClient(host)
+			if err != nil {
+				inboxes[i].Err = err
+				return
+			}
+			inboxes[i].Notifications, inboxes[i].Err = client.Notifications()
+		})
+	}
+	wg.Wait()
+
+	return inboxes
+}
+
+func printNotification(w io.Writer, n vcs.Notification, l tableLayout) {
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	idStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth).Align(lipgloss.Right)
+	timeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
+	reasonStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorYellow))
+	urlStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray)).PaddingLeft(l.keyWidth + 1)
+
+	title := n.Title
+	if n.Type != "" {
+		title = n.Type + ": " + title
+	}
+
+	var s string
+	s += idStyle.Render(n.ID)
+	s += genericStyle.Render(" ")
+	s += titleStyle.Render(truncateString(title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(n.UpdatedAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += reasonStyle.Render(truncateString(n.Reason, l.extraWidth))
+	}
+	fmt.Fprintln(w, s)
+
+	if n.URL != "" {
+		fmt.Fprintln(w, urlStyle.Render(n.URL))
+	}
+}
+
+func printInbox(w io.Writer, inbox hostInbox) {
+	hostStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Bold(true)
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	repoStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorCyan))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+
+	fmt.Fprintln(w, hostStyle.Render(inbox.Host))
+	if inbox.Err != nil {
+		fmt.Fprintln(w, errorStyle.Render(inbox.Err.Error()))
+		return
+	}
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "📬",
+		pluralize(len(inbox.Notifications), "unread notification", "unread notifications"))))
+
+	// align the columns across all repositories
+	var idWidth, reasonWidth int
+	for _, n := range inbox.Notifications {
+		if lipgloss.Width(n.ID) > idWidth {
+			idWidth = lipgloss.Width(n.ID)
+		}
+		if lipgloss.Width(n.Reason) > reasonWidth {
+			reasonWidth = lipgloss.Width(n.Reason)
+		}
+	}
+	l := newTableLayout(idWidth, false, reasonWidth)
+
+	for _, g := range groupNotifications(inbox.Notifications) {
+		fmt.Fprintln(w, repoStyle.Render(g.Repo))
+		for _, n := range g.Notifications {
+			printNotification(w, n, l)
+		}
+	}
+}
+
+func printInboxes(w io.Writer, inboxes []hostInbox) {
+	for i, inbox := range inboxes {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printInbox(w, inbox)
+	}
+}
+
+// markNotificationsRead marks the given notifications of a host as read, or
+// all of them if all is set.
+func markNotificationsRead(client Client, ids []string, all bool) error {
+	switch {
+	case all && len(ids) > 0:
+		return errors.New("can't mark all notifications and single ones as read at once")
+	case all:
+		return client.MarkAllNotificationsRead()
+	case len(ids) == 0:
+		return errors.New("please provide the IDs of the notifications, or --all to mark all of them as read")
+	}
+
+	for _, id := range ids {
+		if err := client.MarkNotificationRead(id); err != nil {
+			return fmt.Errorf("can't mark notification %s as read: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func parseInbox(args []string) {
+	if len(args) > 0 && (args[0] == "done" || args[0] == "read") {
+		fs := flag.NewFlagSet("inbox "+args[0], flag.ExitOnError)
+		all := fs.Bool("all", false, "Mark all notifications of the host as read")
+		fs.Usage = func() {
+			fmt.Fprint(os.Stderr, inboxUsage)
+			fs.PrintDefaults()
+		}
+		_ = fs.Parse(args[1:])
+		if fs.NArg() < 1 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		client, err := guessClient(fs.Arg(0))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := markNotificationsRead(client, fs.Args()[1:], *all); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	hosts := args
+	if len(hosts) == 0 {
+		hosts = configuredHosts()
+	}
+	if len(hosts) == 0 {
+		fmt.Println("Please set a GITTY_TOKENS env var or provide a hostname, e.g. github.com")
+		os.Exit(1)
+	}
+
+	printInboxes(os.Stdout, fetchInboxes(hosts))
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+// inboxClient records which notifications got marked as read.
+type inboxClient struct {
+	Client
+	read    []string
+	readAll bool
+}
+
+func (c *inboxClient) MarkNotificationRead(id string) error {
+	c.read = append(c.read, id)
+	return nil
+}
+
+func (c *inboxClient) MarkAllNotificationsRead() error {
+	c.readAll = true
+	return nil
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	c := &inboxClient{}
+	if err := markNotificationsRead(c, []string{"1", "2"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.read) != 2 || c.readAll {
+		t.Errorf("Expected two notifications to be read, got %v (all: %v)", c.read, c.readAll)
+	}
+
+	// forgetting the IDs must not mark everything as read
+	c = &inboxClient{}
+	if err := markNotificationsRead(c, nil, false); err == nil || c.readAll {
+		t.Error("Expected an error without IDs")
+	}
+
+	if err := markNotificationsRead(c, nil, true); err != nil || !c.readAll {
+		t.Errorf("Expected all notifications to be read, got %v", err)
+	}
+	if err := markNotificationsRead(c, []string{"1"}, true); err == nil {
+		t.Error("Expected an error for IDs along with --all")
+	}
+}
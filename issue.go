@@ -2,41 +2,46 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/gitty/vcs"
-	"github.com/muesli/reflow/truncate"
 )
 
-func printIssue(issue vcs.Issue, maxWidth int) {
+func printIssue(w io.Writer, issue vcs.Issue, l tableLayout, changed bool) {
 	genericStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGray))
 	numberStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorBlue)).Width(maxWidth).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth).Align(lipgloss.Right)
 	timeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorGreen)).Width(8).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(80 - maxWidth)
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
 
 	var s string
+	s += renderMarker(l, changed)
 	s += numberStyle.Render(strconv.Itoa(issue.ID))
 	s += genericStyle.Render(" ")
-	s += titleStyle.Render(truncate.StringWithTail(issue.Title, uint(80-maxWidth), "…"))
-	s += genericStyle.Render(" ")
-	s += timeStyle.Render(ago(issue.CreatedAt))
-	s += genericStyle.Render(" ")
-	s += issue.Labels.View()
+	s += titleStyle.Render(truncateString(issue.Title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(issue.CreatedAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += truncateString(issue.Labels.View(), l.extraWidth)
+	}
 
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 }
 
-func printIssues(issues []vcs.Issue) {
+func printIssues(w io.Writer, issues []vcs.Issue, total int, hl *changeSet) {
 	headerStyle := lipgloss.NewStyle().
 		PaddingTop(1).
 		Foreground(lipgloss.Color(theme.colorMagenta))
 
-	fmt.Println(headerStyle.Render(fmt.Sprintf("%s %s", "🐛", pluralize(len(issues), "open issue", "open issues"))))
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🐛", pluralizeTotal(total, len(issues), "open issue", "open issues"))))
 
 	// trimmed := false
 	if *maxIssues > 0 && len(issues) > *maxIssues {
@@ -44,16 +49,20 @@ func printIssues(issues []vcs.Issue) {
 		// trimmed = true
 	}
 
-	// detect max width of issue number
-	var maxWidth int
+	// detect max width of issue number and labels
+	var maxWidth, labelsWidth int
 	for _, v := range issues {
 		if len(strconv.Itoa(v.ID)) > maxWidth {
 			maxWidth = len(strconv.Itoa(v.ID))
 		}
+		if lw := lipgloss.Width(v.Labels.View()); lw > labelsWidth {
+			labelsWidth = lw
+		}
 	}
+	l := newTableLayout(maxWidth, false, labelsWidth).withMarker(hl != nil)
 
 	for _, v := range issues {
-		printIssue(v, maxWidth)
+		printIssue(w, v, l, hl.issue(v.ID))
 	}
 	// if trimmed {
 	// 	fmt.Println("...")
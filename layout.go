@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+const (
+	defaultWidth  = 100
+	minTitleWidth = 20
+	maxTitleWidth = 120
+
+	ageWidth  = 8
+	statWidth = 9
+)
+
+var outputWidth = defaultWidth
+
+// tableLayout describes how the columns of a list are laid out.
+type tableLayout struct {
+	keyWidth   int
+	titleWidth int
+	showStat   bool
+	showAge    bool
+	extraWidth int
+	marker     bool
+}
+
+// detectWidth returns the width available for rendering. The --width flag
+// takes precedence over the COLUMNS env var, which takes precedence over the
+// size of the attached terminal.
+func detectWidth() int {
+	if *width > 0 {
+		return *width
+	}
+	if c, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && c > 0 {
+		return c
+	}
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+
+	return defaultWidth
+}
+
+// fitColumns distributes the available width between the flexible title
+// column and a list of optional columns. Optional columns are given in
+// descending priority, including their separator, and get dropped from the
+// end until the title column is at least minTitleWidth wide. It returns the
+// title width and the amount of optional columns that remain visible.
+func fitColumns(total int, fixed int, optional ...int) (int, int) {
+	visible := len(optional)
+	for {
+		title := total - fixed
+		for _, w := range optional[:visible] {
+			title -= w
+		}
+
+		if title >= minTitleWidth || visible == 0 {
+			if title < minTitleWidth {
+				title = minTitleWidth
+			}
+			if title > maxTitleWidth {
+				title = maxTitleWidth
+			}
+			return title, visible
+		}
+		visible--
+	}
+}
+
+// newTableLayout computes the layout of a list with a key column of keyWidth
+// and an optional trailing column of at most extraWidth.
+func newTableLayout(keyWidth int, withStat bool, extraWidth int) tableLayout {
+	// the trailing column should never claim more than a third of the screen
+	if extraWidth > outputWidth/3 {
+		extraWidth = outputWidth / 3
+	}
+
+	l := tableLayout{
+		keyWidth: keyWidth,
+	}
+
+	// optional columns in descending priority
+	optional := []int{}
+	if withStat {
+		optional = append(optional, statWidth+1)
+	}
+	optional = append(optional, ageWidth+1)
+	if extraWidth > 0 {
+		optional = append(optional, extraWidth+1)
+	}
+
+	var visible int
+	l.titleWidth, visible = fitColumns(outputWidth, keyWidth+1, optional...)
+
+	if withStat {
+		l.showStat = visible > 0
+		visible--
+	}
+	l.showAge = visible > 0
+	if visible > 1 {
+		l.extraWidth = extraWidth
+	}
+
+	return l
+}
+
+// withMarker reserves space for a marker column in front of each row.
+func (l tableLayout) withMarker(enabled bool) tableLayout {
+	if !enabled {
+		return l
+	}
+
+	l.marker = true
+	l.titleWidth -= markerWidth
+	if l.titleWidth < minTitleWidth {
+		l.titleWidth = minTitleWidth
+	}
+	return l
+}
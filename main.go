@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"os"
@@ -9,7 +10,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/gitty/vcs"
 	"github.com/skratchdot/open-golang/open"
 )
@@ -30,6 +30,17 @@ var (
 	withCommits     = flag.Bool("with-commits", false, "Show new commits")
 	allProjects     = flag.Bool("all-projects", false, "Retrieve information for all source repositories")
 	namespace       = flag.String("namespace", "", "User/organization name when using --all-projects")
+	width           = flag.Int("width", 0, "Width of the output (defaults to the terminal width)")
+	themeName       = flag.String("theme", "", "Color theme: dark, light, high-contrast, monochrome or a theme from the config file")
+	colorProfile    = flag.String("color", "auto", "Color profile: auto, truecolor, 256, 16 or none")
+	templateFile    = flag.String("template", "", "Render the output with a Go text/template file")
+	output          = flag.String("output", "terminal", "Output format: terminal, markdown or html")
+	watch           = flag.Duration("watch", 0, "Refresh the output in the given interval, e.g. 5m")
+	sinceLastRun    = flag.Bool("since-last-run", false, "Only show what changed since the last run")
+	milestone       = flag.String("milestone", "", "Only show issues of the given milestone")
+	concurrency     = flag.Int("concurrency", 8, "Max amount of concurrent requests")
+	stream          = flag.Bool("stream", false, "Print repositories as soon as they're retrieved instead of sorted by release date")
+	workspace       = flag.String("workspace", "", "Summarize all git checkouts found in the given directory")
 
 	version = flag.Bool("version", false, "display version")
 
@@ -93,83 +104,108 @@ func parseRepository() {
 		os.Exit(0)
 	}
 
-	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorCyan))
-	tooltipStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorTooltip))
+	if *watch > 0 {
+		watchRepository(client, arg, rn, host, owner, name)
+		return
+	}
 
-	// fmt.Println(tooltipStyle.Render("🏠 Remote ") + headerStyle.Render(origin))
-	// fmt.Println(tooltipStyle.Render("🔖 Website ") + headerStyle.Render(u))
-	fmt.Println(tooltipStyle.Render("🏠 Repository ") + headerStyle.Render("https://"+host+"/"+owner+"/"+name))
+	o, err := fetchOverview(client, arg, rn, host, owner, name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	compareWithLastRun(o)
 
-	// fetch issues
-	is := make(chan []vcs.Issue)
-	go func() {
-		i, err := client.Issues(owner, name)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		is <- i
-	}()
+	if err := renderOverview(os.Stdout, o); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
 
-	// fetch pull requests
-	prs := make(chan []vcs.PullRequest)
-	go func() {
-		p, err := client.PullRequests(owner, name)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		prs <- p
-	}()
+// projectSource is a host and namespace to retrieve all projects from.
+type projectSource struct {
+	host      string
+	namespace string
+}
 
-	// fetch active branches
-	brs := make(chan []vcs.Branch)
-	go func() {
-		b, err := client.Branches(owner, name)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+// parseProjectSources parses host[:port][:namespace] arguments. Sources
+// without a namespace use the --namespace flag, or the token owner if it is
+// empty. A numeric last part is a port, not a namespace.
+func parseProjectSources(args []string) []projectSource {
+	var sources []projectSource //nolint
+	for _, arg := range args {
+		src := projectSource{host: arg}
+		if i := strings.LastIndex(arg, ":"); i >= 0 {
+			if _, err := strconv.Atoi(arg[i+1:]); err != nil {
+				src.host = arg[:i]
+				src.namespace = arg[i+1:]
+			}
 		}
-		brs <- filterBranches(b)
-	}()
-
-	// get branch stats
-	sts := make(chan map[string]*trackStat)
-	stbrs := make(chan []vcs.Branch)
-	go func() {
-		b := <-brs
-		if s, err := getBranchTrackStats(arg, rn, b); err != nil {
-			stbrs <- b
-			sts <- map[string]*trackStat{}
-		} else {
-			stbrs <- b
-			sts <- s
+		if src.namespace == "" {
+			src.namespace = *namespace
 		}
-	}()
+		sources = append(sources, src)
+	}
+	return sources
+}
 
-	// fetch commit history
-	repo := make(chan vcs.Repo)
-	go func() {
-		r, err := client.Repository(owner, name)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+// fetchReleases retrieves all repositories with a release from a source,
+// including their commits since the last release. found gets called for every
+// repository as soon as it's complete. Repositories whose history can't be
+// retrieved are skipped and reported in the returned error.
+func fetchReleases(src projectSource, pool *workPool, prog *progress, found func(vcs.Repo)) ([]vcs.Repo, error) {
+	client, err := guessClient(src.host)
+	if err != nil {
+		return nil, err
+	}
 
-		r.LastRelease.CommitsSince, err = client.History(r, *maxCommits, r.LastRelease.PublishedAt)
+	if src.namespace == "" {
+		u, err := client.GetUsername()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return nil, fmt.Errorf("can't retrieve profile: %v", err)
 		}
-		repo <- r
-	}()
+		src.namespace = u
+	}
 
-	printIssues(<-is)
-	printPullRequests(<-prs)
-	printBranches(<-stbrs, <-sts)
-	printCommits(<-repo)
+	repos, err := client.Repositories(src.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	wg := &sync.WaitGroup{}
+	mut := &sync.Mutex{}
+	var rr []vcs.Repo
+	var herr error
+
+	// repos with a release
+	repos = vcs.ReposWithRelease(repos)
+	prog.add(len(repos))
+	for _, repo := range repos {
+		repo := repo
+		pool.Go(wg, func() {
+			defer prog.inc()
+
+			var err error
+			repo.Host = src.host
+			repo.LastRelease.CommitsSince, repo.LastRelease.CommitCount, err = commitsSinceRelease(client, repo, *maxCommits)
+
+			mut.Lock()
+			defer mut.Unlock()
+			if err != nil {
+				if herr == nil {
+					herr = fmt.Errorf("can't retrieve history of %s: %v", repo.NameWithOwner, err)
+				}
+				return
+			}
+			rr = append(rr, repo)
+			if found != nil {
+				found(repo)
+			}
+		})
+	}
+
+	wg.Wait()
+	return rr, herr
 }
 
 func parseAllProjects() {
@@ -178,65 +214,127 @@ func parseAllProjects() {
 		fmt.Println("Please provide the hostname of a git provider, e.g. github.com")
 		os.Exit(1)
 	}
+	sources := parseProjectSources(args)
 
-	client, err := guessClient(args[0])
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	if *namespace == "" {
-		u, err := client.GetUsername()
-		if err != nil {
-			fmt.Printf("Can't retrieve profile: %s\n", err)
-			os.Exit(1)
+	var hostWidth int
+	if len(sources) > 1 {
+		for _, src := range sources {
+			if len(src.host) > hostWidth {
+				hostWidth = len(src.host)
+			}
 		}
-		*namespace = u
 	}
 
-	repos, err := client.Repositories(*namespace)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
+	// streaming only makes sense for the plain terminal output
+	streaming := *stream && *templateFile == "" && *output == "terminal"
 
+	pool := newWorkPool(*concurrency)
+	prog := newProgress("Retrieving history of repositories")
 	wg := &sync.WaitGroup{}
 	mut := &sync.Mutex{}
 	var rr []vcs.Repo
+	var streamed int
+	var failed bool
 
-	// repos with a release
-	for _, repo := range vcs.ReposWithRelease(repos) {
-		wg.Add(1)
+	var found func(vcs.Repo)
+	if streaming {
+		found = func(repo vcs.Repo) {
+			mut.Lock()
+			defer mut.Unlock()
 
-		go func(repo vcs.Repo) {
-			var err error
-			repo.LastRelease.CommitsSince, err = client.History(repo, *maxCommits, repo.LastRelease.PublishedAt)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
+			c := compareReleaseWithLastRun(repo.Host, &repo)
+			if *sinceLastRun && c != nil && len(c.commits) == 0 {
+				return
 			}
 
-			mut.Lock()
-			rr = append(rr, repo)
-			mut.Unlock()
+			var buf bytes.Buffer
+			repoRelease(&buf, repo, c, hostWidth)
+			if buf.Len() > 0 {
+				prog.print(os.Stdout, buf.String())
+				streamed++
+			}
+		}
+	}
+
+	for _, src := range sources {
+		wg.Add(1)
+
+		go func(src projectSource) {
+			defer wg.Done()
 
-			wg.Done()
-		}(repo)
+			repos, err := fetchReleases(src, pool, prog, found)
+
+			mut.Lock()
+			defer mut.Unlock()
+			if err != nil {
+				// don't let a single host spoil the report
+				prog.print(os.Stderr, fmt.Sprintf("%s: %s\n", src.host, err))
+				failed = true
+			}
+			rr = append(rr, repos...)
+		}(src)
 	}
 
 	wg.Wait()
-	fmt.Printf("%d repositories with a release:\n", len(rr))
+	prog.finish()
+
+	if streaming {
+		fmt.Printf("%d repositories with a release\n", streamed)
+	} else {
+		sort.Slice(rr, func(i, j int) bool {
+			if rr[i].LastRelease.PublishedAt.Equal(rr[j].LastRelease.PublishedAt) {
+				if rr[i].Name == rr[j].Name {
+					return strings.Compare(rr[i].Host, rr[j].Host) < 0
+				}
+				return strings.Compare(rr[i].Name, rr[j].Name) < 0
+			}
+			return rr[i].LastRelease.PublishedAt.After(rr[j].LastRelease.PublishedAt)
+		})
+
+		// compare with the last run
+		changes := make(map[string]*changeSet, len(rr))
+		var cr []vcs.Repo
+		for _, repo := range rr {
+			c := compareReleaseWithLastRun(repo.Host, &repo)
+			if *sinceLastRun && c != nil && len(c.commits) == 0 {
+				continue
+			}
+
+			changes[repo.Host+"/"+repo.NameWithOwner] = c
+			cr = append(cr, repo)
+		}
 
-	sort.Slice(rr, func(i, j int) bool {
-		if rr[i].LastRelease.PublishedAt.Equal(rr[j].LastRelease.PublishedAt) {
-			return strings.Compare(rr[i].Name, rr[j].Name) < 0
+		if err := renderReleases(cr, changes, hostWidth); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
 		}
-		return rr[i].LastRelease.PublishedAt.After(rr[j].LastRelease.PublishedAt)
-	})
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// renderReleases renders the release report in the format requested by the
+// user. A hostWidth of 0 hides the host column.
+func renderReleases(rr []vcs.Repo, changes map[string]*changeSet, hostWidth int) error {
+	if *templateFile != "" {
+		return renderTemplate(os.Stdout, *templateFile, rr)
+	}
+
+	switch *output {
+	case "markdown":
+		writeMarkdownReleases(os.Stdout, rr, hostWidth > 0)
+		return nil
+	case "html":
+		return writeHTMLReleases(os.Stdout, rr, hostWidth > 0)
+	}
 
+	fmt.Printf("%d repositories with a release:\n", len(rr))
 	for _, repo := range rr {
-		repoRelease(repo)
+		repoRelease(os.Stdout, repo, changes[repo.Host+"/"+repo.NameWithOwner], hostWidth)
 	}
+	return nil
 }
 
 func printVersion() {
@@ -258,6 +356,15 @@ func printVersion() {
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: gitty [PATH|URL] [ISSUE|PR]\n"+
+			"       gitty open TARGET [ARG]\n"+
+			"       gitty show [--web] [--pr] NUMBER\n"+
+			"       gitty checkout NUMBER\n"+
+			"       gitty issue create [FLAGS]\n"+
+			"       gitty pr create [FLAGS]\n"+
+			"       gitty me [HOST...]\n"+
+			"       gitty inbox [HOST...]\n"+
+			"       gitty inbox done [--all] HOST [ID...]\n"+
+			"       gitty rate-limit [HOST...]\n"+
 			"Contextual information about your git projects, right on the command-line.\n\n")
 		flag.PrintDefaults()
 	}
@@ -268,8 +375,63 @@ func main() {
 		os.Exit(0)
 	}
 
-	initTheme()
+	switch *output {
+	case "terminal", "markdown", "html":
+	default:
+		fmt.Printf("Unknown output format: %s\n", *output)
+		os.Exit(1)
+	}
+
+	if err := loadConfig(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := validateCredentialOrder(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := initColorProfile(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := initTheme(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	outputWidth = detectWidth()
 
+	if flag.Arg(0) == "open" {
+		parseOpen(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "show" {
+		parseShow(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "issue" || flag.Arg(0) == "pr" {
+		parseCreate(flag.Arg(0), flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "checkout" {
+		parseCheckout(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "me" {
+		parseMe(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "inbox" {
+		parseInbox(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "rate-limit" {
+		parseRateLimits(flag.Args()[1:])
+		os.Exit(0)
+	}
+	if *workspace != "" {
+		parseWorkspace(*workspace)
+		os.Exit(0)
+	}
 	if *allProjects {
 		parseAllProjects()
 		os.Exit(0)
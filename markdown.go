@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `&lt;`,
+	">", `&gt;`,
+	"|", `\|`,
+	"#", `\#`,
+)
+
+// mdLink returns a markdown link to u, or just the text if u is empty.
+func mdLink(text, u string) string {
+	if u == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, u)
+}
+
+func mdLabels(labels vcs.Labels) string {
+	var s []string
+	for _, l := range labels {
+		s = append(s, "`"+strings.ReplaceAll(l.Name, "`", "'")+"`")
+	}
+	return strings.Join(s, " ")
+}
+
+func shortSHA(id string) string {
+	if len(id) > 7 {
+		return id[:7]
+	}
+	return id
+}
+
+func writeMarkdownOverview(w io.Writer, o *Overview) {
+	fmt.Fprintf(w, "# %s\n", mdLink(markdownEscaper.Replace(o.Owner+"/"+o.Name), o.URL))
+
+	// pull request of the checked out branch
+	if pr := o.BranchPullRequest; pr != nil {
+		fmt.Fprintf(w, "\n## 🔀 Pull request for %s\n\n", markdownEscaper.Replace(pr.Branch))
+		fmt.Fprintf(w, "%s %s\n\n", mdLink("#"+strconv.Itoa(pr.ID), pr.URL), markdownEscaper.Replace(pr.Title))
+		fmt.Fprintln(w, pullRequestStatusText(pr))
+	}
+
+	// pipelines
+	if len(o.Pipelines) > 0 {
+		fmt.Fprintf(w, "\n## 🚦 %s\n\n", pluralize(len(o.Pipelines), "pipeline", "pipelines"))
+		fmt.Fprintln(w, "| Branch | Status | Pipeline | Duration |")
+		fmt.Fprintln(w, "|--------|--------|----------|---------:|")
+	}
+	for _, v := range o.Pipelines {
+		title := v.Name
+		if v.FailedJob != "" && v.FailedJob != v.Name {
+			title += ": " + v.FailedJob
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			markdownEscaper.Replace(v.Branch), v.Status,
+			mdLink(markdownEscaper.Replace(title), v.URL), pipelineDuration(v))
+	}
+
+	// issues
+	issues := o.Issues
+	fmt.Fprintf(w, "\n## 🐛 %s\n\n", pluralizeTotal(o.IssueCount, len(o.Issues), "open issue", "open issues"))
+	if *maxIssues > 0 && len(issues) > *maxIssues {
+		issues = issues[:*maxIssues]
+	}
+	if len(issues) > 0 {
+		fmt.Fprintln(w, "| # | Title | Age | Labels |")
+		fmt.Fprintln(w, "|--:|-------|----:|--------|")
+	}
+	for _, v := range issues {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			mdLink("#"+strconv.Itoa(v.ID), v.URL), markdownEscaper.Replace(v.Title),
+			ago(v.CreatedAt), mdLabels(v.Labels))
+	}
+
+	// pull requests
+	prs := o.PullRequests
+	fmt.Fprintf(w, "\n## 📌 %s\n\n", pluralizeTotal(o.PullRequestCount, len(o.PullRequests), "open pull request", "open pull requests"))
+	if *maxPullRequests > 0 && len(prs) > *maxPullRequests {
+		prs = prs[:*maxPullRequests]
+	}
+	if len(prs) > 0 {
+		fmt.Fprintln(w, "| # | Title | Age | Labels |")
+		fmt.Fprintln(w, "|--:|-------|----:|--------|")
+	}
+	for _, v := range prs {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			mdLink("#"+strconv.Itoa(v.ID), v.URL), markdownEscaper.Replace(v.Title),
+			ago(v.CreatedAt), mdLabels(v.Labels))
+	}
+
+	// milestones
+	if len(o.Milestones) > 0 {
+		fmt.Fprintf(w, "\n## 🎯 %s\n\n", pluralize(len(o.Milestones), "open milestone", "open milestones"))
+		fmt.Fprintln(w, "| Milestone | Progress | Due | Issues |")
+		fmt.Fprintln(w, "|-----------|---------:|----:|-------:|")
+	}
+	for _, v := range o.Milestones {
+		fmt.Fprintf(w, "| %s | %d%% | %s | %d/%d |\n",
+			mdLink(markdownEscaper.Replace(v.Title), v.URL), int(v.Progress()*100),
+			due(v), v.ClosedIssues, v.ClosedIssues+v.OpenIssues)
+	}
+
+	// branches
+	branches := o.Branches
+	if *maxBranches > 0 && len(branches) > *maxBranches {
+		branches = branches[:*maxBranches]
+	}
+	fmt.Fprintf(w, "\n## 🌳 %s\n\n", pluralize(len(branches), "active branch", "active branches"))
+	if len(branches) > 0 {
+		fmt.Fprintln(w, "| Branch | Last commit | Age | Author |")
+		fmt.Fprintln(w, "|--------|-------------|----:|--------|")
+	}
+	for _, v := range branches {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			mdLink(markdownEscaper.Replace(v.Name), v.URL),
+			mdLink(markdownEscaper.Replace(v.LastCommit.MessageHeadline), v.LastCommit.URL),
+			ago(v.LastCommit.CommittedAt), markdownEscaper.Replace(v.LastCommit.Author))
+	}
+
+	// commits
+	sinceTag := o.Repo.LastRelease.TagName
+	if sinceTag == "" {
+		sinceTag = "creation"
+	} else {
+		sinceTag = mdLink(markdownEscaper.Replace(sinceTag), o.Repo.LastRelease.URL)
+	}
+	fmt.Fprintf(w, "\n## 🔥 %s %s (%s)\n\n",
+		pluralize(o.Repo.LastRelease.CommitCount, "commit since", "commits since"),
+		sinceTag, relTime(o.Repo.LastRelease.PublishedAt))
+	writeMarkdownCommits(w, o.Commits, "")
+}
+
+func writeMarkdownCommits(w io.Writer, commits []vcs.Commit, indent string) {
+	if *maxCommits > 0 && len(commits) > *maxCommits {
+		commits = commits[:*maxCommits]
+	}
+
+	for _, v := range commits {
+		fmt.Fprintf(w, "%s- %s %s (%s, %s)\n", indent,
+			mdLink("`"+shortSHA(v.ID)+"`", v.URL), markdownEscaper.Replace(v.MessageHeadline),
+			markdownEscaper.Replace(v.Author), ago(v.CommittedAt))
+	}
+}
+
+// writeMarkdownReleases writes the release report as Markdown. withHost adds
+// the host to every repository, to tell apart repositories with the same name.
+func writeMarkdownReleases(w io.Writer, repos []vcs.Repo, withHost bool) {
+	var rr []vcs.Repo
+	for _, repo := range repos {
+		if *skipStaleRepos && repo.LastRelease.CommitCount < *minNewCommits {
+			continue
+		}
+		rr = append(rr, repo)
+	}
+
+	fmt.Fprintf(w, "# %s\n\n", pluralize(len(rr), "repository with a release", "repositories with a release"))
+	for _, repo := range rr {
+		fmt.Fprint(w, "- ")
+		if withHost {
+			fmt.Fprintf(w, "%s ", markdownEscaper.Replace(repo.Host))
+		}
+		fmt.Fprintf(w, "%s %s (%s, %d new commits since)\n",
+			mdLink("**"+markdownEscaper.Replace(repo.Name)+"**", repo.URL),
+			mdLink(markdownEscaper.Replace(repo.LastRelease.TagName), repo.LastRelease.URL),
+			relTime(repo.LastRelease.PublishedAt), repo.LastRelease.CommitCount)
+
+		if *withCommits {
+			writeMarkdownCommits(w, repo.LastRelease.CommitsSince, "  ")
+		}
+	}
+}
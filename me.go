@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+// hostDashboard is the open work of the token owner on a host.
+type hostDashboard struct {
+	Host      string
+	User      string
+	Dashboard vcs.Dashboard
+	Err       error
+}
+
+// fetchDashboards retrieves the dashboards of the token owners on all given
+// hosts.
+func fetchDashboards(hosts []string) []hostDashboard {
+	dashboards := make([]hostDashboard, len(hosts))
+	pool := newWorkPool(*concurrency)
+	wg := &sync.WaitGroup{}
+	for i, host := range hosts {
+		i, host := i, host
+		pool.Go(wg, func() {
+			d := &dashboards[i]
+			d.Host = host
+
+			client, err := guessClient(host)
+			if err != nil {
+				d.Err = err
+				return
+			}
+			d.User, err = client.GetUsername()
+			if err != nil {
+				d.Err = fmt.Errorf("can't retrieve username: %v", err)
+				return
+			}
+			d.Dashboard, d.Err = client.Dashboard(d.User)
+		})
+	}
+	wg.Wait()
+
+	return dashboards
+}
+
+// pullRequestStatusIcons renders the review, CI and merge status of a pull
+// request as icons.
+func pullRequestStatusIcons(pr *vcs.PullRequestStatus) string {
+	var s string
+	for _, p := range pullRequestStatus(pr) {
+		if p.icon == "💬" {
+			continue
+		}
+		if s != "" {
+			s += " "
+		}
+		s += lipgloss.NewStyle().
+			Foreground(lipgloss.Color(p.color)).
+			Render(p.icon)
+	}
+	return s
+}
+
+func printDashboardRow(w io.Writer, l tableLayout, key string, title string, createdAt time.Time, extra string) {
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth)
+	timeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
+
+	var s string
+	s += keyStyle.Render(key)
+	s += genericStyle.Render(" ")
+	s += titleStyle.Render(truncateString(title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(createdAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += truncateString(extra, l.extraWidth)
+	}
+
+	fmt.Fprintln(w, s)
+}
+
+func printDashboardPullRequests(w io.Writer, header string, prs []vcs.UserPullRequest) {
+	headerStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	fmt.Fprintln(w, headerStyle.Render(header))
+
+	var keyWidth, extraWidth int
+	for _, v := range prs {
+		if kw := lipgloss.Width(v.Repo + "#" + strconv.Itoa(v.ID)); kw > keyWidth {
+			keyWidth = kw
+		}
+		if ew := lipgloss.Width(pullRequestStatusIcons(&v.PullRequestStatus)); ew > extraWidth {
+			extraWidth = ew
+		}
+	}
+	l := newTableLayout(keyWidth, false, extraWidth)
+
+	for _, v := range prs {
+		printDashboardRow(w, l, v.Repo+"#"+strconv.Itoa(v.ID), v.Title, v.CreatedAt,
+			pullRequestStatusIcons(&v.PullRequestStatus))
+	}
+}
+
+func printDashboard(w io.Writer, d hostDashboard) {
+	hostStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Bold(true)
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+	headerStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorMagenta))
+
+	s := hostStyle.Render(d.Host)
+	if d.User != "" {
+		s += genericStyle.Render(" @" + d.User)
+	}
+	fmt.Fprintln(w, s)
+	if d.Err != nil {
+		fmt.Fprintln(w, errorStyle.Render(d.Err.Error()))
+		return
+	}
+
+	// assigned issues
+	issues := d.Dashboard.Assigned
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🐛", pluralize(len(issues), "assigned issue", "assigned issues"))))
+
+	var keyWidth, labelsWidth int
+	for _, v := range issues {
+		if kw := lipgloss.Width(v.Repo + "#" + strconv.Itoa(v.ID)); kw > keyWidth {
+			keyWidth = kw
+		}
+		if lw := lipgloss.Width(v.Labels.View()); lw > labelsWidth {
+			labelsWidth = lw
+		}
+	}
+	l := newTableLayout(keyWidth, false, labelsWidth)
+	for _, v := range issues {
+		printDashboardRow(w, l, v.Repo+"#"+strconv.Itoa(v.ID), v.Title, v.CreatedAt, v.Labels.View())
+	}
+
+	printDashboardPullRequests(w, fmt.Sprintf("%s %s", "👀",
+		pluralize(len(d.Dashboard.ReviewRequests), "pull request awaiting your review", "pull requests awaiting your review")),
+		d.Dashboard.ReviewRequests)
+	printDashboardPullRequests(w, fmt.Sprintf("%s %s", "📌",
+		pluralize(len(d.Dashboard.Authored), "open pull request of yours", "open pull requests of yours")),
+		d.Dashboard.Authored)
+}
+
+func printDashboards(w io.Writer, dashboards []hostDashboard) {
+	for i, d := range dashboards {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printDashboard(w, d)
+	}
+}
+
+func parseMe(hosts []string) {
+	if len(hosts) == 0 {
+		hosts = configuredHosts()
+	}
+	if len(hosts) == 0 {
+		fmt.Println("Please set a GITTY_TOKENS env var or provide a hostname, e.g. github.com")
+		os.Exit(1)
+	}
+
+	printDashboards(os.Stdout, fetchDashboards(hosts))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+const progressBarWidth = 10
+
+// due returns a short representation of when a milestone is due.
+func due(m vcs.Milestone) string {
+	if m.DueOn.IsZero() {
+		return ""
+	}
+
+	d := m.DueOn.Sub(now())
+	if d < 0 {
+		return ago(m.DueOn) + " late"
+	}
+	s := ago(now().Add(-d))
+	if s == "now" {
+		return s
+	}
+	return "in " + s
+}
+
+// progressBar renders a bar that is filled by the given share.
+func progressBar(progress float64, width int) string {
+	filledStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen))
+	emptyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+
+	filled := int(math.Round(progress * float64(width)))
+	return filledStyle.Render(strings.Repeat("█", filled)) +
+		emptyStyle.Render(strings.Repeat("░", width-filled))
+}
+
+func printMilestone(w io.Writer, m vcs.Milestone, l tableLayout) {
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	percentStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(4).Align(lipgloss.Right)
+	timeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
+	countStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue))
+
+	if !m.DueOn.IsZero() && m.DueOn.Before(now()) && m.OpenIssues > 0 {
+		timeStyle = timeStyle.Foreground(lipgloss.Color(theme.colorRed))
+	}
+
+	var s string
+	s += progressBar(m.Progress(), progressBarWidth)
+	s += percentStyle.Render(fmt.Sprintf("%d%%", int(m.Progress()*100)))
+	s += genericStyle.Render(" ")
+	s += titleStyle.Render(truncateString(m.Title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(due(m))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += countStyle.Render(fmt.Sprintf("%d/%d", m.ClosedIssues, m.ClosedIssues+m.OpenIssues))
+	}
+
+	fmt.Fprintln(w, s)
+}
+
+func printMilestones(w io.Writer, milestones []vcs.Milestone) {
+	if len(milestones) == 0 {
+		// milestones aren't used by everyone
+		return
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorMagenta))
+
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🎯", pluralize(len(milestones), "open milestone", "open milestones"))))
+
+	// detect max width of the issue counts
+	var countWidth int
+	for _, v := range milestones {
+		if c := len(fmt.Sprintf("%d/%d", v.ClosedIssues, v.ClosedIssues+v.OpenIssues)); c > countWidth {
+			countWidth = c
+		}
+	}
+	l := newTableLayout(progressBarWidth+4, false, countWidth)
+
+	for _, v := range milestones {
+		printMilestone(w, v, l)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const openUsage = `Usage: gitty open TARGET [ARG]
+
+Targets:
+  repo             the repository
+  branch [NAME]    a branch, defaults to the current one
+  commit [REV]     a commit, defaults to HEAD
+  release          the latest release
+  compare [REF]    the changes since the latest release, up to the current branch
+  ci [BRANCH]      the CI pipelines of a branch, defaults to the current one
+  pr               a new pull request for the current branch
+`
+
+// currentBranch returns the name of the checked out branch of a local
+// repository.
+func currentBranch(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("can't determine the current branch: HEAD is detached")
+	}
+	return head.Name().Short(), nil
+}
+
+// pushedBranch returns the name the checked out branch of a local repository
+// has on its remote. That's the name of the branch it tracks if git pushes to
+// the upstream branch, otherwise the branch's own name.
+func pushedBranch(path string) (string, error) {
+	branch, err := currentBranch(path)
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := repo.ConfigScoped(gitconfig.GlobalScope)
+	if err != nil {
+		return branch, nil //nolint:nilerr
+	}
+	switch cfg.Raw.Section("push").Option("default") {
+	case "upstream", "tracking":
+		if b, ok := cfg.Branches[branch]; ok && b.Merge != "" {
+			return b.Merge.Short(), nil
+		}
+	}
+	return branch, nil
+}
+
+// resolveCommit returns the full SHA of a revision of a local repository. If
+// it can't be resolved locally, rev is returned as is.
+func resolveCommit(path string, rev string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		if rev == "" {
+			return "", err
+		}
+		return rev, nil
+	}
+
+	if rev == "" {
+		rev = "HEAD"
+	}
+	h, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		if rev == "HEAD" {
+			return "", err
+		}
+		// might only exist remotely
+		return rev, nil //nolint:nilerr
+	}
+	return h.String(), nil
+}
+
+// openURL returns the URL of a target of `gitty open`.
+func openURL(client Client, path, owner, name, target string, args []string) (string, error) {
+	var arg string
+	if len(args) > 0 {
+		arg = args[0]
+	}
+
+	switch target {
+	case "repo":
+		return client.RepositoryURL(owner, name), nil
+
+	case "branch", "ci":
+		branch := arg
+		if branch == "" {
+			var err error
+			branch, err = pushedBranch(path)
+			if err != nil {
+				return "", err
+			}
+		}
+		if target == "ci" {
+			return client.PipelinesURL(owner, name, branch), nil
+		}
+		return client.BranchURL(owner, name, branch), nil
+
+	case "commit":
+		sha, err := resolveCommit(path, arg)
+		if err != nil {
+			return "", err
+		}
+		return client.CommitURL(owner, name, sha), nil
+
+	case "release", "compare":
+		r, err := client.Repository(owner, name)
+		if err != nil {
+			return "", err
+		}
+		if r.LastRelease.TagName == "" {
+			return "", fmt.Errorf("%s/%s has no releases yet", owner, name)
+		}
+		if target == "release" {
+			return client.ReleaseURL(owner, name, r.LastRelease.TagName), nil
+		}
+
+		head := arg
+		if head == "" {
+			head, err = pushedBranch(path)
+			if err != nil {
+				// compare with the checked out commit instead
+				head, err = resolveCommit(path, "")
+				if err != nil {
+					return "", err
+				}
+			}
+		}
+		return client.CompareURL(owner, name, r.LastRelease.TagName, head), nil
+
+	case "pr":
+		head, err := pushedBranch(path)
+		if err != nil {
+			return "", err
+		}
+		r, err := client.Repository(owner, name)
+		if err != nil {
+			return "", err
+		}
+		if head == r.DefaultBranch {
+			return "", fmt.Errorf("can't open a pull request for the default branch %s", head)
+		}
+		return client.NewPullRequestURL(owner, name, r.DefaultBranch, head), nil
+	}
+
+	return "", fmt.Errorf("unknown target: %s", target)
+}
+
+// parseOpen opens a target of the repository in the current directory in the
+// browser.
+func parseOpen(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, openUsage)
+		os.Exit(1)
+	}
+
+	host, owner, name, _, err := parseRepo(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	client, err := guessClient(host)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	u, err := openURL(client, ".", owner, name, args[0], args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := open.Start(u); err != nil {
+		fmt.Println("URL:", u)
+	}
+}
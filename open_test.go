@@ -0,0 +1,210 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/muesli/gitty/vcs"
+	"github.com/muesli/gitty/vcs/github"
+)
+
+// fakeClient answers repository lookups locally and builds URLs with the
+// embedded client.
+type fakeClient struct {
+	Client
+	repo     vcs.Repo
+	branchPR *vcs.PullRequestStatus
+	username string
+}
+
+func (c fakeClient) GetUsername() (string, error) {
+	return c.username, nil
+}
+
+func (c fakeClient) Repository(owner string, name string) (vcs.Repo, error) {
+	return c.repo, nil
+}
+
+func (c fakeClient) PullRequestForBranch(owner string, name string, branch string, headOwners []string) (*vcs.PullRequestStatus, error) {
+	if c.branchPR == nil || c.branchPR.Branch != branch || !vcs.OwnedBy(c.branchPR.HeadRepo, headOwners) {
+		return nil, nil
+	}
+	return c.branchPR, nil
+}
+
+// initTestRepo creates a repository with a single commit, checked out on the
+// given branch.
+func initTestRepo(t *testing.T, branch string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "README.md"), "gitty")
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	h, err := wt.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "muesli", Email: "muesli@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if branch != "master" {
+		if err := wt.Checkout(&git.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(branch),
+			Create: true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, h.String()
+}
+
+func TestOpenURL(t *testing.T) {
+	dir, sha := initTestRepo(t, "feature/open")
+
+	gh, err := github.NewClient("github.com", "token", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fakeClient{
+		Client: gh,
+		repo: vcs.Repo{
+			DefaultBranch: "master",
+			LastRelease:   vcs.Release{TagName: "v0.7.0"},
+		},
+	}
+
+	tt := []struct {
+		target string
+		args   []string
+		exp    string
+	}{
+		{"repo", nil, "https://github.com/muesli/gitty"},
+		{"branch", nil, "https://github.com/muesli/gitty/tree/feature/open"},
+		{"branch", []string{"master"}, "https://github.com/muesli/gitty/tree/master"},
+		{"commit", nil, "https://github.com/muesli/gitty/commit/" + sha},
+		{"commit", []string{"abc1234"}, "https://github.com/muesli/gitty/commit/abc1234"},
+		{"release", nil, "https://github.com/muesli/gitty/releases/tag/v0.7.0"},
+		{"compare", nil, "https://github.com/muesli/gitty/compare/v0.7.0...feature/open"},
+		{"ci", nil, "https://github.com/muesli/gitty/actions?query=branch%3Afeature%2Fopen"},
+		{"pr", nil, "https://github.com/muesli/gitty/compare/master...feature/open?expand=1"},
+	}
+
+	for _, test := range tt {
+		u, err := openURL(client, dir, "muesli", "gitty", test.target, test.args)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", test.target, err)
+			continue
+		}
+		if u != test.exp {
+			t.Errorf("Expected %s for %s %v, got %s", test.exp, test.target, test.args, u)
+		}
+	}
+}
+
+// A branch created from another one tracks it, but doesn't get pushed to it
+// unless git is told to.
+func TestPushedBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir, _ := initTestRepo(t, "feat")
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Branches["feat"] = &gitconfig.Branch{
+		Name:   "feat",
+		Remote: "origin",
+		Merge:  plumbing.NewBranchReferenceName("main"),
+	}
+	if err := repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		pushDefault string
+		exp         string
+	}{
+		{"", "feat"},
+		{"upstream", "main"},
+	} {
+		cfg.Raw.Section("push").SetOption("default", test.pushDefault)
+		if err := repo.SetConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		if branch, err := currentBranch(dir); err != nil || branch != "feat" {
+			t.Errorf("Expected current branch feat, got %s (%v)", branch, err)
+		}
+		if branch, err := pushedBranch(dir); err != nil || branch != test.exp {
+			t.Errorf("Expected branch %s to be pushed with push.default %q, got %s (%v)", test.exp, test.pushDefault, branch, err)
+		}
+	}
+}
+
+func TestOpenURLErrors(t *testing.T) {
+	dir, _ := initTestRepo(t, "master")
+
+	gh, err := github.NewClient("github.com", "token", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fakeClient{
+		Client: gh,
+		repo:   vcs.Repo{DefaultBranch: "master"},
+	}
+
+	for _, target := range []string{"pr", "release", "compare", "issues"} {
+		if _, err := openURL(client, dir, "muesli", "gitty", target, nil); err == nil {
+			t.Errorf("Expected an error for %s", target)
+		}
+	}
+
+	// not a local repository
+	if _, err := openURL(client, os.TempDir(), "muesli", "gitty", "branch", nil); err == nil {
+		t.Error("Expected an error without a local repository")
+	}
+}
+
+func TestEnterpriseURLs(t *testing.T) {
+	gh, err := github.NewClient("github.example.com", "token", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		url string
+		exp string
+	}{
+		{gh.RepositoryURL("muesli", "gitty"), "https://github.example.com/muesli/gitty"},
+		{gh.IssueURL("muesli", "gitty", 42), "https://github.example.com/muesli/gitty/issues/42"},
+		{gh.BranchURL("muesli", "gitty", "feature/open"), "https://github.example.com/muesli/gitty/tree/feature/open"},
+	}
+
+	for _, test := range tt {
+		if test.url != test.exp {
+			t.Errorf("Expected %s, got %s", test.exp, test.url)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+// Overview contains everything gitty knows about a repository.
+type Overview struct {
+	Host         string
+	Owner        string
+	Name         string
+	URL          string
+	Repo         vcs.Repo
+	Issues       []vcs.Issue
+	PullRequests []vcs.PullRequest
+	Branches     []vcs.Branch
+	Stats        map[string]*trackStat
+	Commits      []vcs.Commit
+	Pipelines    []vcs.Pipeline
+	Milestones   []vcs.Milestone
+
+	// the total amount of open issues and pull requests, which may exceed
+	// the amount of retrieved ones
+	IssueCount       int
+	PullRequestCount int
+
+	// the pull request of the checked out branch, if any
+	BranchPullRequest *vcs.PullRequestStatus
+
+	// changes since a previous state, if any
+	changes *changeSet
+	lastRun time.Time
+}
+
+// summaryClient is implemented by clients that can retrieve most of an
+// overview in a single request.
+type summaryClient interface {
+	Summary(owner string, name string, maxIssues, maxPullRequests int) (vcs.Summary, error)
+}
+
+// canSummarize reports whether a summary holds everything the overview shows.
+// Summaries are limited to 100 issues and pull requests and can't be filtered
+// by milestone.
+func canSummarize() bool {
+	return *maxIssues > 0 && *maxIssues <= 100 &&
+		*maxPullRequests > 0 && *maxPullRequests <= 100 &&
+		*milestone == ""
+}
+
+// fetchOverview retrieves all information about a repository. path and remote
+// refer to the local checkout, if there is one.
+func fetchOverview(client Client, path, remote, host, owner, name string) (*Overview, error) {
+	o := &Overview{
+		Host:  host,
+		Owner: owner,
+		Name:  name,
+		URL:   "https://" + host + "/" + owner + "/" + name,
+	}
+
+	var err error
+	if sc, ok := client.(summaryClient); ok && canSummarize() {
+		err = fetchSummary(client, sc, o)
+	} else {
+		err = fetchItems(client, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// get branch stats
+	sts := make(chan map[string]*trackStat)
+	go func() {
+		s, err := getBranchTrackStats(path, remote, o.Branches)
+		if err != nil {
+			s = map[string]*trackStat{}
+		}
+		sts <- s
+	}()
+
+	bpr := make(chan *vcs.PullRequestStatus)
+	go func() {
+		bpr <- fetchBranchPullRequest(client, path, remote, owner, name, o.Repo.DefaultBranch)
+	}()
+
+	o.Pipelines = fetchPipelines(client, owner, name, o.Repo.DefaultBranch, o.Branches)
+	o.BranchPullRequest = <-bpr
+	o.Stats = <-sts
+	return o, nil
+}
+
+// fetchSummary retrieves the repository, its issues, pull requests, branches
+// and milestones in a single request, and the commits since its last release
+// in a second one.
+func fetchSummary(client Client, sc summaryClient, o *Overview) error {
+	s, err := sc.Summary(o.Owner, o.Name, *maxIssues, *maxPullRequests)
+	if err != nil {
+		return err
+	}
+
+	o.Repo = s.Repo
+	o.Issues = s.Issues
+	o.IssueCount = s.IssueCount
+	o.PullRequests = s.PullRequests
+	o.PullRequestCount = s.PullRequestCount
+	o.Branches = filterBranches(s.Branches)
+	o.Milestones = s.Milestones
+
+	o.Repo.LastRelease.CommitsSince, o.Repo.LastRelease.CommitCount, err = commitsSinceRelease(client, o.Repo, *maxCommits)
+	if err != nil {
+		return err
+	}
+	o.Commits = o.Repo.LastRelease.CommitsSince
+	return nil
+}
+
+// fetchItems concurrently retrieves the repository, its issues, pull
+// requests, branches and milestones one by one. Only as many items as will be
+// shown get retrieved.
+func fetchItems(client Client, o *Overview) error {
+	owner, name := o.Owner, o.Name
+
+	// fetch issues
+	is := make(chan []vcs.Issue)
+	errs := make(chan error, 4)
+	go func() {
+		i, total, err := client.Issues(owner, name, vcs.ListOptions{
+			Limit:     *maxIssues,
+			Milestone: *milestone,
+		})
+		if err != nil {
+			errs <- err
+		}
+		o.IssueCount = total
+		is <- i
+	}()
+
+	// fetch pull requests
+	prs := make(chan []vcs.PullRequest)
+	go func() {
+		p, total, err := client.PullRequests(owner, name, vcs.ListOptions{Limit: *maxPullRequests})
+		if err != nil {
+			errs <- err
+		}
+		o.PullRequestCount = total
+		prs <- p
+	}()
+
+	// fetch active branches
+	brs := make(chan []vcs.Branch)
+	go func() {
+		b, err := client.Branches(owner, name, vcs.ListOptions{
+			Limit: *maxBranches,
+			Sort:  vcs.SortUpdated,
+		})
+		if err != nil {
+			errs <- err
+		}
+		brs <- filterBranches(b)
+	}()
+
+	// fetch milestones
+	ms := make(chan []vcs.Milestone)
+	go func() {
+		// milestones are an extra, so don't fail the overview
+		m, err := client.Milestones(owner, name)
+		if err != nil {
+			debugf("can't retrieve milestones of %s/%s: %v", owner, name, err)
+		}
+		ms <- m
+	}()
+
+	// fetch commit history
+	repo := make(chan vcs.Repo)
+	go func() {
+		r, err := client.Repository(owner, name)
+		if err != nil {
+			errs <- err
+			repo <- r
+			return
+		}
+
+		r.LastRelease.CommitsSince, r.LastRelease.CommitCount, err = commitsSinceRelease(client, r, *maxCommits)
+		if err != nil {
+			errs <- err
+		}
+		repo <- r
+	}()
+
+	o.Issues = <-is
+	o.PullRequests = <-prs
+	o.Branches = <-brs
+	o.Milestones = <-ms
+	o.Repo = <-repo
+	o.Commits = o.Repo.LastRelease.CommitsSince
+
+	close(errs)
+	return <-errs
+}
+
+func printOverview(w io.Writer, o *Overview) {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorCyan))
+	tooltipStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorTooltip))
+
+	// fmt.Println(tooltipStyle.Render("🏠 Remote ") + headerStyle.Render(origin))
+	// fmt.Println(tooltipStyle.Render("🔖 Website ") + headerStyle.Render(u))
+	fmt.Fprintln(w, tooltipStyle.Render("🏠 Repository ")+headerStyle.Render(o.URL))
+	if *sinceLastRun && !o.lastRun.IsZero() {
+		fmt.Fprintln(w, tooltipStyle.Render("🕑 Changes since last run ")+headerStyle.Render(relTime(o.lastRun)))
+	}
+
+	// what you're working on and failing CI are the first things you want to
+	// know about
+	printBranchPullRequest(w, o.BranchPullRequest)
+	printPipelines(w, o.Pipelines)
+	printIssues(w, o.Issues, o.IssueCount, o.changes)
+	printPullRequests(w, o.PullRequests, o.PullRequestCount, o.changes)
+	printMilestones(w, o.Milestones)
+	printBranches(w, o.Branches, o.Stats, o.changes)
+	printCommits(w, o.Repo, o.changes)
+}
+
+// renderOverview renders the overview in the format requested by the user.
+func renderOverview(w io.Writer, o *Overview) error {
+	if *templateFile != "" {
+		return renderTemplate(w, *templateFile, o)
+	}
+
+	switch *output {
+	case "markdown":
+		writeMarkdownOverview(w, o)
+	case "html":
+		return writeHTMLOverview(w, o)
+	default:
+		printOverview(w, o)
+	}
+	return nil
+}
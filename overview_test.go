@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// summaryFakeClient answers summaries locally and counts them.
+type summaryFakeClient struct {
+	compareClient
+	summary vcs.Summary
+	calls   *int
+}
+
+func (c summaryFakeClient) Summary(owner string, name string, maxIssues, maxPullRequests int) (vcs.Summary, error) {
+	*c.calls++
+	return c.summary, nil
+}
+
+func TestFetchSummary(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	branches, _ := testBranches()
+	commits := testCommits()
+	var calls int
+	client := summaryFakeClient{
+		compareClient: compareClient{compared: commits, total: 42},
+		summary: vcs.Summary{
+			Repo: vcs.Repo{
+				DefaultBranch: "master",
+				LastRelease:   vcs.Release{TagName: "v0.7.0"},
+			},
+			Issues:           testIssues(),
+			IssueCount:       250,
+			PullRequests:     testPullRequests(),
+			PullRequestCount: 30,
+			Branches:         branches,
+			Milestones:       testMilestones(),
+		},
+		calls: &calls,
+	}
+
+	if !canSummarize() {
+		t.Fatal("Expected the default flags to allow a summary")
+	}
+
+	o := &Overview{Owner: "muesli", Name: "gitty"}
+	if err := fetchSummary(client, client, o); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single summary request, got %d", calls)
+	}
+	if o.IssueCount != 250 || len(o.Issues) != len(testIssues()) {
+		t.Errorf("Expected %d of 250 issues, got %d of %d", len(testIssues()), len(o.Issues), o.IssueCount)
+	}
+	if o.PullRequestCount != 30 {
+		t.Errorf("Expected 30 pull requests, got %d", o.PullRequestCount)
+	}
+	if o.Repo.LastRelease.CommitCount != 42 || len(o.Commits) != len(commits) {
+		t.Errorf("Expected %d of 42 commits, got %d of %d", len(commits), len(o.Commits), o.Repo.LastRelease.CommitCount)
+	}
+
+	// paginate if more items were requested than a summary holds
+	*maxIssues = 200
+	defer func() { *maxIssues = 10 }()
+	if canSummarize() {
+		t.Error("Expected no summary for more than 100 issues")
+	}
+}
+
+// itemsFakeClient lists items locally, but can't retrieve milestones.
+type itemsFakeClient struct {
+	compareClient
+}
+
+func (c itemsFakeClient) Issues(owner string, name string, opts vcs.ListOptions) ([]vcs.Issue, int, error) {
+	return testIssues(), len(testIssues()), nil
+}
+
+func (c itemsFakeClient) PullRequests(owner string, name string, opts vcs.ListOptions) ([]vcs.PullRequest, int, error) {
+	return testPullRequests(), len(testPullRequests()), nil
+}
+
+func (c itemsFakeClient) Branches(owner string, name string, opts vcs.ListOptions) ([]vcs.Branch, error) {
+	return nil, nil
+}
+
+func (c itemsFakeClient) Milestones(owner string, name string) ([]vcs.Milestone, error) {
+	return nil, errors.New("milestones are disabled")
+}
+
+func (c itemsFakeClient) Repository(owner string, name string) (vcs.Repo, error) {
+	return vcs.Repo{DefaultBranch: "master"}, nil
+}
+
+func TestFetchItemsWithoutMilestones(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	o := &Overview{Owner: "muesli", Name: "gitty"}
+	if err := fetchItems(itemsFakeClient{}, o); err != nil {
+		t.Fatalf("Expected the overview to ignore missing milestones, got %v", err)
+	}
+	if len(o.Issues) != len(testIssues()) || len(o.Milestones) != 0 {
+		t.Errorf("Expected %d issues and no milestones, got %d and %d", len(testIssues()), len(o.Issues), len(o.Milestones))
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+// fetchPipelines retrieves the latest CI runs of the default branch and the
+// active branches. Branches without CI are left out.
+func fetchPipelines(client Client, owner, name, defaultBranch string, branches []vcs.Branch) []vcs.Pipeline {
+	names := []string{}
+	if defaultBranch != "" {
+		names = append(names, defaultBranch)
+	}
+	for i, b := range branches {
+		if *maxBranches > 0 && i >= *maxBranches {
+			break
+		}
+		if b.Name != defaultBranch {
+			names = append(names, b.Name)
+		}
+	}
+
+	// not every token can see CI, so don't fail the overview
+	pipelines, _ := client.Pipelines(owner, name, names)
+	return pipelines
+}
+
+// pipelineDuration returns how long a pipeline ran, or has been running so far.
+func pipelineDuration(p vcs.Pipeline) time.Duration {
+	if p.StartedAt.IsZero() {
+		return 0
+	}
+	if p.FinishedAt.IsZero() {
+		return now().Sub(p.StartedAt).Round(time.Second)
+	}
+	return p.FinishedAt.Sub(p.StartedAt).Round(time.Second)
+}
+
+func pipelineIcon(status vcs.PipelineStatus) (string, string) {
+	switch status {
+	case vcs.PipelineSuccess:
+		return "✔", theme.colorGreen
+	case vcs.PipelineFailed:
+		return "✘", theme.colorRed
+	case vcs.PipelineRunning:
+		return "●", theme.colorYellow
+	case vcs.PipelinePending:
+		return "○", theme.colorYellow
+	default:
+		return "–", theme.colorGray
+	}
+}
+
+func printPipeline(w io.Writer, p vcs.Pipeline, l tableLayout) {
+	icon, color := pipelineIcon(p.Status)
+
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	statusStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(color)).Width(markerWidth)
+	branchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth)
+	timeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
+	durationStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue))
+
+	title := p.Name
+	if p.FailedJob != "" && p.FailedJob != p.Name {
+		title += ": " + p.FailedJob
+	}
+	if p.Status == vcs.PipelineFailed {
+		titleStyle = titleStyle.Foreground(lipgloss.Color(theme.colorRed))
+	}
+
+	var s string
+	s += statusStyle.Render(icon)
+	s += branchStyle.Render(p.Branch)
+	s += genericStyle.Render(" ")
+	s += titleStyle.Render(truncateString(title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		if p.FinishedAt.IsZero() {
+			s += timeStyle.Render(string(p.Status))
+		} else {
+			s += timeStyle.Render(ago(p.FinishedAt))
+		}
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		if d := pipelineDuration(p); d > 0 {
+			s += durationStyle.Render(d.String())
+		}
+	}
+
+	fmt.Fprintln(w, s)
+}
+
+func printPipelines(w io.Writer, pipelines []vcs.Pipeline) {
+	if len(pipelines) == 0 {
+		// no CI configured
+		return
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorMagenta))
+
+	var failed int
+	var maxWidth, durationWidth int
+	for _, v := range pipelines {
+		if v.Status == vcs.PipelineFailed {
+			failed++
+		}
+		if len(v.Branch) > maxWidth {
+			maxWidth = len(v.Branch)
+		}
+		if d := len(pipelineDuration(v).String()); d > durationWidth {
+			durationWidth = d
+		}
+	}
+
+	header := pluralize(len(pipelines), "pipeline", "pipelines")
+	if failed > 0 {
+		header += fmt.Sprintf(", %d failing", failed)
+	}
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🚦", header)))
+
+	// the status icon takes the place of the marker column
+	l := newTableLayout(maxWidth, false, durationWidth).withMarker(true)
+	for _, v := range pipelines {
+		printPipeline(w, v, l)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// workPool limits how many functions run concurrently.
+type workPool struct {
+	sem chan struct{}
+}
+
+func newWorkPool(size int) *workPool {
+	if size < 1 {
+		size = 1
+	}
+	return &workPool{sem: make(chan struct{}, size)}
+}
+
+// Go runs fn in its own goroutine as soon as a worker is available. wg gets
+// notified when fn is done.
+func (p *workPool) Go(wg *sync.WaitGroup, fn func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		p.sem <- struct{}{}
+		defer func() { <-p.sem }()
+		fn()
+	}()
+}
+
+// progress reports how many jobs are done on a single, continuously updated
+// line. It stays silent unless w is a terminal.
+type progress struct {
+	sync.Mutex
+	w       io.Writer
+	label   string
+	enabled bool
+	total   int
+	done    int
+}
+
+func newProgress(label string) *progress {
+	return &progress{
+		w:       os.Stderr,
+		label:   label,
+		enabled: term.IsTerminal(int(os.Stderr.Fd())),
+	}
+}
+
+// add announces n more jobs.
+func (p *progress) add(n int) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.total += n
+	p.render()
+}
+
+// inc marks a job as done.
+func (p *progress) inc() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.done++
+	p.render()
+}
+
+// print prints s to w without garbling the progress line.
+func (p *progress) print(w io.Writer, s string) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.clear()
+	fmt.Fprint(w, s)
+	p.render()
+}
+
+// finish removes the progress line.
+func (p *progress) finish() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.clear()
+	p.enabled = false
+}
+
+func (p *progress) render() {
+	if !p.enabled {
+		return
+	}
+	fmt.Fprintf(p.w, "\r%s %d/%d", p.label, p.done, p.total)
+}
+
+func (p *progress) clear() {
+	if !p.enabled {
+		return
+	}
+	fmt.Fprint(p.w, "\r\x1b[K")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkPool(t *testing.T) {
+	pool := newWorkPool(3)
+
+	var mut sync.Mutex
+	var running, peak, done int
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		pool.Go(wg, func() {
+			mut.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mut.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mut.Lock()
+			running--
+			done++
+			mut.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if done != 20 {
+		t.Errorf("Expected 20 jobs to be done, got %d", done)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent jobs, got %d", peak)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var buf, out bytes.Buffer
+	p := &progress{w: &buf, label: "Fetching", enabled: true}
+	p.add(2)
+	p.inc()
+	p.print(&out, "gitty\n")
+	p.inc()
+	p.finish()
+
+	exp := "\rFetching 0/2\rFetching 1/2\r\x1b[K\rFetching 1/2\rFetching 2/2\r\x1b[K"
+	if buf.String() != exp {
+		t.Errorf("Expected progress %q, got %q", exp, buf.String())
+	}
+	if out.String() != "gitty\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	// disabled progress doesn't write anything
+	buf.Reset()
+	p = &progress{w: &buf}
+	p.add(1)
+	p.inc()
+	p.finish()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no progress, got %q", buf.String())
+	}
+}
@@ -2,41 +2,47 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/gitty/vcs"
-	"github.com/muesli/reflow/truncate"
 )
 
-func printPullRequest(pr vcs.PullRequest, maxWidth int) {
+func printPullRequest(w io.Writer, pr vcs.PullRequest, l tableLayout, changed bool) {
 	genericStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGray))
 	numberStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorBlue)).Width(maxWidth).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorBlue)).Width(l.keyWidth).Align(lipgloss.Right)
 	timeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorGreen)).Width(8).Align(lipgloss.Right)
+		Foreground(lipgloss.Color(theme.colorGreen)).Width(ageWidth).Align(lipgloss.Right)
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(80 - maxWidth)
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(l.titleWidth)
 
 	var s string
+	s += renderMarker(l, changed)
 	s += numberStyle.Render(strconv.Itoa(pr.ID))
 	s += genericStyle.Render(" ")
-	s += titleStyle.Render(truncate.StringWithTail(pr.Title, uint(80-maxWidth), "…"))
-	s += genericStyle.Render(" ")
-	s += timeStyle.Render(ago(pr.CreatedAt))
-	s += genericStyle.Render(" ")
-	s += pr.Labels.View()
+	s += titleStyle.Render(truncateString(pr.Title, l.titleWidth))
+	if l.showAge {
+		s += genericStyle.Render(" ")
+		s += timeStyle.Render(ago(pr.CreatedAt))
+	}
+	if l.extraWidth > 0 {
+		s += genericStyle.Render(" ")
+		s += truncateString(pr.Labels.View(), l.extraWidth)
+	}
 
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 }
 
-func printPullRequests(prs []vcs.PullRequest) {
+func printPullRequests(w io.Writer, prs []vcs.PullRequest, total int, hl *changeSet) {
 	headerStyle := lipgloss.NewStyle().
 		PaddingTop(1).
 		Foreground(lipgloss.Color(theme.colorMagenta))
 
-	fmt.Println(headerStyle.Render(fmt.Sprintf("%s %s", "📌", pluralize(len(prs), "open pull request", "open pull requests"))))
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "📌", pluralizeTotal(total, len(prs), "open pull request", "open pull requests"))))
 
 	// trimmed := false
 	if *maxPullRequests > 0 && len(prs) > *maxPullRequests {
@@ -44,18 +50,159 @@ func printPullRequests(prs []vcs.PullRequest) {
 		// trimmed = true
 	}
 
-	// detect max width of pr number
-	var maxWidth int
+	// detect max width of pr number and labels
+	var maxWidth, labelsWidth int
 	for _, v := range prs {
 		if len(strconv.Itoa(v.ID)) > maxWidth {
 			maxWidth = len(strconv.Itoa(v.ID))
 		}
+		if lw := lipgloss.Width(v.Labels.View()); lw > labelsWidth {
+			labelsWidth = lw
+		}
 	}
+	l := newTableLayout(maxWidth, false, labelsWidth).withMarker(hl != nil)
 
 	for _, v := range prs {
-		printPullRequest(v, maxWidth)
+		printPullRequest(w, v, l, hl.pullRequest(v.ID))
 	}
 	// if trimmed {
 	// 	fmt.Println("...")
 	// }
 }
+
+// fetchBranchPullRequest retrieves the open pull request of the branch checked
+// out at path. It returns nil if there is no local checkout, the default
+// branch is checked out or no pull request was opened from the branch. Only
+// pull requests from the repository itself or the user's fork count, others
+// may use the same branch name.
+func fetchBranchPullRequest(client Client, path, remote, owner, name, defaultBranch string) *vcs.PullRequestStatus {
+	if remote == "" {
+		return nil
+	}
+	branch, err := pushedBranch(path)
+	if err != nil || branch == defaultBranch {
+		return nil
+	}
+
+	headOwners := []string{owner}
+	if u, err := client.GetUsername(); err == nil && u != "" {
+		headOwners = append(headOwners, u)
+	}
+
+	pr, _ := client.PullRequestForBranch(owner, name, branch, headOwners)
+	return pr
+}
+
+// reviewIcon returns the icon and color representing a review decision.
+func reviewIcon(status vcs.ReviewStatus) (string, string) {
+	switch status {
+	case vcs.ReviewApproved:
+		return "✔", theme.colorGreen
+	case vcs.ReviewChangesRequested:
+		return "✘", theme.colorRed
+	case vcs.ReviewRequired:
+		return "○", theme.colorYellow
+	default:
+		return "–", theme.colorGray
+	}
+}
+
+// mergeIcon returns the icon and color representing whether a pull request can
+// be merged.
+func mergeIcon(status vcs.MergeStatus) (string, string) {
+	switch status {
+	case vcs.MergeClean:
+		return "✔", theme.colorGreen
+	case vcs.MergeConflicting:
+		return "✘", theme.colorRed
+	default:
+		return "?", theme.colorGray
+	}
+}
+
+// statusPart is a single aspect of a pull request's status.
+type statusPart struct {
+	icon  string
+	color string
+	text  string
+}
+
+// pullRequestStatus returns the review, CI, comment and merge status of a pull
+// request.
+func pullRequestStatus(pr *vcs.PullRequestStatus) []statusPart {
+	var parts []statusPart
+	if pr.Draft {
+		parts = append(parts, statusPart{"✎", theme.colorGray, "draft"})
+	}
+
+	icon, color := reviewIcon(pr.Review)
+	text := string(pr.Review)
+	if pr.Review == "" {
+		text = "no reviews"
+	}
+	parts = append(parts, statusPart{icon, color, text})
+
+	if pr.CI != "" {
+		icon, color = pipelineIcon(pr.CI)
+		parts = append(parts, statusPart{icon, color, "CI " + string(pr.CI)})
+	}
+
+	parts = append(parts, statusPart{"💬", theme.colorGray, pluralize(pr.Comments, "comment", "comments")})
+
+	icon, color = mergeIcon(pr.Mergeable)
+	text = string(pr.Mergeable)
+	if pr.Mergeable == vcs.MergeUnknown {
+		text = "mergeability unknown"
+	}
+	parts = append(parts, statusPart{icon, color, text})
+
+	return parts
+}
+
+// pullRequestStatusText returns the status of a pull request as plain text.
+func pullRequestStatusText(pr *vcs.PullRequestStatus) string {
+	var parts []string
+	for _, p := range pullRequestStatus(pr) {
+		parts = append(parts, p.text)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// printBranchPullRequest prints the pull request of the checked out branch.
+func printBranchPullRequest(w io.Writer, pr *vcs.PullRequestStatus) {
+	if pr == nil {
+		return
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		PaddingTop(1).
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s Pull request for %s", "🔀", pr.Branch)))
+
+	l := newTableLayout(len(strconv.Itoa(pr.ID)), false, lipgloss.Width(pr.Labels.View()))
+	printPullRequest(w, pr.PullRequest, l, false)
+
+	// align the status with the title
+	s := genericStyle.Render(strings.Repeat(" ", l.keyWidth+1))
+	s += renderPullRequestStatus(pr)
+	fmt.Fprintln(w, s)
+}
+
+// renderPullRequestStatus renders the status of a pull request with its icons.
+func renderPullRequestStatus(pr *vcs.PullRequestStatus) string {
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray))
+
+	var parts []string
+	for _, p := range pullRequestStatus(pr) {
+		iconStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(p.color))
+		parts = append(parts, iconStyle.Render(p.icon)+textStyle.Render(" "+p.text))
+	}
+	return strings.Join(parts, genericStyle.Render(" · "))
+}
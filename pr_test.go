@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestFetchBranchPullRequest(t *testing.T) {
+	client := fakeClient{
+		branchPR: &vcs.PullRequestStatus{
+			PullRequest: vcs.PullRequest{ID: 42},
+			Branch:      "feature/branch-pr",
+			HeadRepo:    "muesli/gitty",
+		},
+		username: "muesli",
+	}
+
+	dir, _ := initTestRepo(t, "feature/branch-pr")
+	if pr := fetchBranchPullRequest(client, dir, "origin", "muesli", "gitty", "master"); pr == nil || pr.ID != 42 {
+		t.Errorf("Expected pull request 42, got %v", pr)
+	}
+
+	// a pull request from the user's fork
+	client.branchPR.HeadRepo = "MUESLI/gitty-fork"
+	client.username = "muesli"
+	if pr := fetchBranchPullRequest(client, dir, "origin", "charmbracelet", "gitty", "master"); pr == nil || pr.ID != 42 {
+		t.Errorf("Expected pull request 42 from the user's fork, got %v", pr)
+	}
+
+	// someone else's fork using the same branch name
+	client.branchPR.HeadRepo = "someone/gitty"
+	if pr := fetchBranchPullRequest(client, dir, "origin", "muesli", "gitty", "master"); pr != nil {
+		t.Errorf("Expected no pull request from another fork, got %v", pr)
+	}
+	client.branchPR.HeadRepo = "muesli/gitty"
+
+	// not a local checkout
+	if pr := fetchBranchPullRequest(client, dir, "", "muesli", "gitty", "master"); pr != nil {
+		t.Errorf("Expected no pull request without a checkout, got %v", pr)
+	}
+
+	// the default branch has no pull request of its own
+	dir, _ = initTestRepo(t, "master")
+	if pr := fetchBranchPullRequest(client, dir, "origin", "muesli", "gitty", "master"); pr != nil {
+		t.Errorf("Expected no pull request for the default branch, got %v", pr)
+	}
+}
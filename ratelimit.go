@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+)
+
+// hostRateLimit is the API quota of a host.
+type hostRateLimit struct {
+	Host  string
+	Limit vcs.RateLimit
+	Err   error
+}
+
+// fetchRateLimits retrieves the API quota of all given hosts.
+func fetchRateLimits(hosts []string) []hostRateLimit {
+	limits := make([]hostRateLimit, len(hosts))
+	pool := newWorkPool(*concurrency)
+	wg := &sync.WaitGroup{}
+	for i, host := range hosts {
+		i, host := i, host
+		pool.Go(wg, func() {
+			limits[i].Host = host
+
+			client, err := guessClient(host)
+			if err != nil {
+				limits[i].Err = err
+				return
+			}
+			limits[i].Limit, limits[i].Err = client.RateLimit()
+		})
+	}
+	wg.Wait()
+
+	return limits
+}
+
+func printRateLimits(w io.Writer, limits []hostRateLimit) {
+	hostStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue))
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	quotaStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+
+	var hostWidth int
+	for _, l := range limits {
+		if lipgloss.Width(l.Host) > hostWidth {
+			hostWidth = lipgloss.Width(l.Host)
+		}
+	}
+	hostStyle = hostStyle.Width(hostWidth)
+
+	for _, l := range limits {
+		s := hostStyle.Render(l.Host) + genericStyle.Render(" ")
+
+		switch {
+		case l.Err != nil:
+			s += errorStyle.Render(l.Err.Error())
+		case !l.Limit.Known():
+			s += genericStyle.Render("No rate limit reported")
+		default:
+			switch {
+			case l.Limit.Remaining < l.Limit.Limit/10:
+				quotaStyle = quotaStyle.Foreground(lipgloss.Color(theme.colorRed))
+			case l.Limit.Remaining < l.Limit.Limit/2:
+				quotaStyle = quotaStyle.Foreground(lipgloss.Color(theme.colorYellow))
+			default:
+				quotaStyle = quotaStyle.Foreground(lipgloss.Color(theme.colorGreen))
+			}
+
+			s += quotaStyle.Render(fmt.Sprintf("%d/%d", l.Limit.Remaining, l.Limit.Limit))
+			s += genericStyle.Render(" requests left")
+			if !l.Limit.Reset.IsZero() {
+				s += genericStyle.Render(", resets " + relTime(l.Limit.Reset))
+			}
+		}
+
+		fmt.Fprintln(w, s)
+	}
+}
+
+func parseRateLimits(hosts []string) {
+	if len(hosts) == 0 {
+		hosts = configuredHosts()
+	}
+	if len(hosts) == 0 {
+		fmt.Println("Please set a GITTY_TOKENS env var or provide a hostname, e.g. github.com")
+		os.Exit(1)
+	}
+
+	printRateLimits(os.Stdout, fetchRateLimits(hosts))
+}
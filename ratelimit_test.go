@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestPrintRateLimits(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printRateLimits(&buf, []hostRateLimit{
+		{Host: "github.com", Limit: vcs.RateLimit{Limit: 5000, Remaining: 4321, Reset: fixedNow.Add(42 * time.Minute)}},
+		{Host: "gitlab.com", Limit: vcs.RateLimit{Limit: 2000, Remaining: 150}},
+		{Host: "codeberg.org"},
+		{Host: "example.com", Err: errors.New("not a recognized git provider")},
+	})
+	assertGolden(t, "rate_limits", buf.Bytes())
+}
+
+func TestConfiguredHosts(t *testing.T) {
+	resetTokenCache(t)
+	t.Setenv("GITTY_TOKENS", "gitlab.com=abc; codeberg.org=def")
+	t.Setenv("GITTY_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "ghi")
+
+	hosts := configuredHosts()
+	exp := []string{"gitlab.com", "codeberg.org", "github.com"}
+	if len(hosts) != len(exp) {
+		t.Fatalf("Expected %v, got %v", exp, hosts)
+	}
+	for i := range exp {
+		if hosts[i] != exp[i] {
+			t.Errorf("Expected %v, got %v", exp, hosts)
+		}
+	}
+}
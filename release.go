@@ -2,14 +2,16 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 	"github.com/muesli/gitty/vcs"
 )
 
-func repoRelease(repo vcs.Repo) {
+// repoRelease prints a repo's latest release. A hostWidth of 0 hides the host
+// column.
+func repoRelease(w io.Writer, repo vcs.Repo, changes *changeSet, hostWidth int) {
 	genericStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGray))
 	repoStyle := lipgloss.NewStyle().
@@ -20,11 +22,13 @@ func repoRelease(repo vcs.Repo) {
 		Foreground(lipgloss.Color(theme.colorGreen))
 	changesStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.colorGreen))
+	hostStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray)).Width(hostWidth)
 
 	day := time.Hour * 24
 	week := day * 7
 	month := week * 4
-	since := time.Since(repo.LastRelease.PublishedAt)
+	since := now().Sub(repo.LastRelease.PublishedAt)
 	switch {
 	case since > month*6:
 		dateStyle = dateStyle.Foreground(lipgloss.Color(theme.colorRed))
@@ -33,13 +37,13 @@ func repoRelease(repo vcs.Repo) {
 	}
 
 	switch {
-	case len(repo.LastRelease.CommitsSince) > 32:
+	case repo.LastRelease.CommitCount > 32:
 		changesStyle = changesStyle.Foreground(lipgloss.Color(theme.colorRed))
-	case len(repo.LastRelease.CommitsSince) > 16:
+	case repo.LastRelease.CommitCount > 16:
 		changesStyle = changesStyle.Foreground(lipgloss.Color(theme.colorYellow))
 	}
 
-	if len(repo.LastRelease.CommitsSince) < *minNewCommits {
+	if repo.LastRelease.CommitCount < *minNewCommits {
 		if *skipStaleRepos {
 			return
 		}
@@ -51,24 +55,32 @@ func repoRelease(repo vcs.Repo) {
 	}
 
 	var s string
+	if changes != nil {
+		s += renderMarker(tableLayout{marker: true}, len(changes.commits) > 0)
+	}
+	if hostWidth > 0 {
+		s += hostStyle.Render(repo.Host)
+		s += genericStyle.Render(" ")
+	}
 	s += repoStyle.Render(repo.Name)
 	s += versionStyle.Render(" " + repo.LastRelease.TagName)
 	s += genericStyle.Render(" (")
-	s += dateStyle.Render(humanize.Time(repo.LastRelease.PublishedAt))
+	s += dateStyle.Render(relTime(repo.LastRelease.PublishedAt))
 	s += genericStyle.Render(", ")
-	s += changesStyle.Render(fmt.Sprintf("%d new commits since", len(repo.LastRelease.CommitsSince)))
+	s += changesStyle.Render(fmt.Sprintf("%d new commits since", repo.LastRelease.CommitCount))
 	s += genericStyle.Render(")")
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 
 	if *withCommits && len(repo.LastRelease.CommitsSince) > 0 {
+		l := commitsLayout(repo.LastRelease.CommitsSince).withMarker(changes != nil)
 		for i, commit := range repo.LastRelease.CommitsSince {
 			if i >= *maxCommits && *maxCommits > 0 {
 				break
 			}
 
-			printCommit(commit)
+			printCommit(w, commit, l, changes.commit(commit.ID))
 		}
 
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
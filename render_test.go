@@ -0,0 +1,690 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var fixedNow = time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+func setupRenderTest(t *testing.T, themeName string) {
+	t.Helper()
+
+	prevNow := now
+	prevTheme := theme
+	prevProfile := lipgloss.ColorProfile()
+	prevWidth := outputWidth
+	t.Cleanup(func() {
+		now = prevNow
+		theme = prevTheme
+		lipgloss.SetColorProfile(prevProfile)
+		outputWidth = prevWidth
+	})
+
+	now = func() time.Time { return fixedNow }
+	theme = themes[themeName]
+	outputWidth = defaultWidth
+	lipgloss.SetColorProfile(termenv.TrueColor)
+}
+
+func setIntFlag(t *testing.T, f *int, v int) {
+	t.Helper()
+
+	prev := *f
+	*f = v
+	t.Cleanup(func() { *f = prev })
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading golden file: %s", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output does not match %s:\n%s\nexpected:\n%s", path, got, want)
+	}
+}
+
+func testIssues() []vcs.Issue {
+	return []vcs.Issue{
+		{
+			ID:        1234,
+			URL:       "https://github.com/muesli/gitty/issues/1234",
+			Title:     "A rather long issue title that is going to be truncated because it exceeds the available width",
+			CreatedAt: fixedNow.Add(-3 * time.Hour),
+			Labels: vcs.Labels{
+				{Name: "bug", Color: "#d73a4a"},
+				{Name: "help wanted", Color: "#008672"},
+			},
+		},
+		{
+			ID:        56,
+			URL:       "https://github.com/muesli/gitty/issues/56",
+			Title:     "Short <b>title</b> | with [markup]",
+			CreatedAt: fixedNow.Add(-9 * 24 * time.Hour),
+		},
+		{
+			ID:        7,
+			Title:     "Recently opened",
+			CreatedAt: fixedNow.Add(-30 * time.Second),
+			Labels: vcs.Labels{
+				{Name: "enhancement", Color: "#a2eeef"},
+			},
+		},
+	}
+}
+
+func testPullRequests() []vcs.PullRequest {
+	var prs []vcs.PullRequest
+	for _, i := range testIssues() {
+		prs = append(prs, vcs.PullRequest{
+			ID:        i.ID,
+			URL:       i.URL,
+			Title:     i.Title,
+			CreatedAt: i.CreatedAt,
+			Labels:    i.Labels,
+		})
+	}
+	return prs
+}
+
+func testCommits() []vcs.Commit {
+	return []vcs.Commit{
+		{
+			ID:              "0123456789abcdef0123456789abcdef01234567",
+			URL:             "https://github.com/muesli/gitty/commit/0123456789abcdef0123456789abcdef01234567",
+			MessageHeadline: "Fix a bug in the renderer that caused very long commit messages to wrap around",
+			CommittedAt:     fixedNow.Add(-2 * time.Hour),
+			Author:          "muesli",
+		},
+		{
+			ID:              "fedcba9876543210fedcba9876543210fedcba98",
+			MessageHeadline: "Update dependencies",
+			CommittedAt:     fixedNow.Add(-50 * 24 * time.Hour),
+			Author:          "dependabot",
+		},
+	}
+}
+
+func testBranches() ([]vcs.Branch, map[string]*trackStat) {
+	commits := testCommits()
+	branches := []vcs.Branch{
+		{Name: "master", LastCommit: commits[0], URL: "https://github.com/muesli/gitty/tree/master"},
+		{Name: "feature/long-branch-name", LastCommit: commits[1]},
+		{Name: "wip", LastCommit: commits[1]},
+	}
+	stats := map[string]*trackStat{
+		"master":                   {},
+		"feature/long-branch-name": {Outdated: true, Ahead: 3, Behind: 120},
+		"wip":                      nil,
+	}
+	return branches, stats
+}
+
+func testPipelines() []vcs.Pipeline {
+	return []vcs.Pipeline{
+		{
+			Branch:     "master",
+			Name:       "build",
+			Status:     vcs.PipelineFailed,
+			FailedJob:  "test (windows-latest)",
+			StartedAt:  fixedNow.Add(-2 * time.Hour),
+			FinishedAt: fixedNow.Add(-2*time.Hour + 4*time.Minute + 12*time.Second),
+			URL:        "https://github.com/muesli/gitty/actions/runs/1",
+		},
+		{
+			Branch:    "feature-branch",
+			Name:      "build",
+			Status:    vcs.PipelineRunning,
+			StartedAt: fixedNow.Add(-90 * time.Second),
+		},
+		{
+			Branch:     "fix-render",
+			Name:       "lint",
+			Status:     vcs.PipelineSuccess,
+			StartedAt:  fixedNow.Add(-26 * time.Hour),
+			FinishedAt: fixedNow.Add(-26*time.Hour + 58*time.Second),
+		},
+	}
+}
+
+func testMilestones() []vcs.Milestone {
+	return []vcs.Milestone{
+		{
+			ID:           1,
+			Title:        "v0.8.0",
+			DueOn:        fixedNow.Add(-3 * 24 * time.Hour),
+			OpenIssues:   2,
+			ClosedIssues: 6,
+			URL:          "https://github.com/muesli/gitty/milestone/1",
+		},
+		{
+			ID:           2,
+			Title:        "v1.0.0",
+			DueOn:        fixedNow.Add(30 * 24 * time.Hour),
+			OpenIssues:   9,
+			ClosedIssues: 1,
+		},
+		{
+			ID:    3,
+			Title: "Someday",
+		},
+	}
+}
+
+func testBranchPullRequest() *vcs.PullRequestStatus {
+	return &vcs.PullRequestStatus{
+		PullRequest: vcs.PullRequest{
+			ID:        42,
+			Title:     "Show the pull request of the current branch",
+			CreatedAt: fixedNow.Add(-3 * 24 * time.Hour),
+			URL:       "https://github.com/muesli/gitty/pull/42",
+			Labels:    vcs.Labels{{Name: "enhancement", Color: "#a2eeef"}},
+		},
+		Branch:    "feature/branch-pr",
+		Review:    vcs.ReviewChangesRequested,
+		CI:        vcs.PipelineFailed,
+		Comments:  3,
+		Mergeable: vcs.MergeClean,
+	}
+}
+
+func testIssueDetails() *issueDetails {
+	return &issueDetails{
+		Issue: vcs.Issue{
+			ID:        7,
+			Title:     "Crash when the remote has no default branch",
+			Author:    "octocat",
+			State:     "open",
+			Body:      "Running `gitty` in a fresh clone crashes:\n\n```\npanic: runtime error\n```\n\nSteps to reproduce:\n\n1. Create an empty repository\n2. Run **gitty** in its clone",
+			Milestone: "v1.0.0",
+			CreatedAt: fixedNow.Add(-5 * 24 * time.Hour),
+			Labels:    vcs.Labels{{Name: "bug", Color: "#d73a4a"}},
+		},
+		Comments: []vcs.Comment{
+			{
+				Author:    "muesli",
+				Body:      "Thanks for the report! Can you share the output of `git remote -v`?",
+				CreatedAt: fixedNow.Add(-4 * 24 * time.Hour),
+			},
+			{
+				Author:    "octocat",
+				Body:      "Sure, it's just `origin`.",
+				CreatedAt: fixedNow.Add(-2 * time.Hour),
+			},
+		},
+	}
+}
+
+func testDashboards() []hostDashboard {
+	pr := *testBranchPullRequest()
+	return []hostDashboard{
+		{
+			Host: "github.com",
+			User: "muesli",
+			Dashboard: vcs.Dashboard{
+				Assigned: []vcs.AssignedIssue{
+					{Issue: testIssues()[0], Repo: "muesli/gitty"},
+					{Issue: testIssues()[1], Repo: "charmbracelet/glow"},
+				},
+				ReviewRequests: []vcs.UserPullRequest{
+					{PullRequestStatus: pr, Repo: "charmbracelet/lipgloss"},
+				},
+			},
+		},
+		{
+			Host: "gitlab.com",
+			Err:  errors.New("401 Unauthorized"),
+		},
+	}
+}
+
+func testInboxes() []hostInbox {
+	return []hostInbox{
+		{
+			Host: "github.com",
+			Notifications: []vcs.Notification{
+				{
+					ID:        "6789012345",
+					Repo:      "muesli/gitty",
+					Title:     "Show the pull request of the current branch",
+					Type:      "pull request",
+					Reason:    "review requested",
+					UpdatedAt: fixedNow.Add(-2 * time.Hour),
+					URL:       "https://github.com/muesli/gitty/pull/42",
+				},
+				{
+					ID:        "6789012346",
+					Repo:      "charmbracelet/glow",
+					Title:     "v1.5.0",
+					Type:      "release",
+					Reason:    "subscribed",
+					UpdatedAt: fixedNow.Add(-3 * 24 * time.Hour),
+					URL:       "https://github.com/charmbracelet/glow/releases",
+				},
+				{
+					ID:        "6789012347",
+					Repo:      "muesli/gitty",
+					Title:     "Crash when the remote has no default branch",
+					Type:      "issue",
+					Reason:    "mention",
+					UpdatedAt: fixedNow.Add(-30 * time.Minute),
+					URL:       "https://github.com/muesli/gitty/issues/7",
+				},
+			},
+		},
+		{
+			Host: "gitlab.com",
+		},
+	}
+}
+
+func testOverview() *Overview {
+	branches, stats := testBranches()
+	commits := testCommits()
+	return &Overview{
+		Host:             "github.com",
+		Owner:            "muesli",
+		Name:             "gitty",
+		URL:              "https://github.com/muesli/gitty",
+		Issues:           testIssues(),
+		PullRequests:     testPullRequests(),
+		IssueCount:       len(testIssues()),
+		PullRequestCount: len(testPullRequests()),
+		Branches:         branches,
+		Stats:            stats,
+		Commits:          commits,
+		Pipelines:        testPipelines(),
+		Milestones:       testMilestones(),
+
+		BranchPullRequest: testBranchPullRequest(),
+		Repo: vcs.Repo{
+			Owner:         "muesli",
+			Name:          "gitty",
+			NameWithOwner: "muesli/gitty",
+			URL:           "https://github.com/muesli/gitty",
+			LastRelease: vcs.Release{
+				TagName:      "v0.7.0",
+				URL:          "https://github.com/muesli/gitty/releases/tag/v0.7.0",
+				PublishedAt:  fixedNow.Add(-10 * 24 * time.Hour),
+				CommitsSince: commits,
+				CommitCount:  len(commits),
+			},
+		},
+	}
+}
+
+var renderThemes = []string{"dark", "light", "high-contrast"}
+
+func TestPrintIssues(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printIssues(&buf, testIssues(), len(testIssues()), nil)
+			assertGolden(t, "issues_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintIssuesTrimmed(t *testing.T) {
+	setupRenderTest(t, "dark")
+	setIntFlag(t, maxIssues, 2)
+
+	var buf bytes.Buffer
+	printIssues(&buf, testIssues(), len(testIssues()), nil)
+	assertGolden(t, "issues_trimmed", buf.Bytes())
+}
+
+func TestPrintIssuesUnknownTotal(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printIssues(&buf, testIssues(), vcs.UnknownTotal, nil)
+	assertGolden(t, "issues_unknown_total", buf.Bytes())
+}
+
+func TestPrintIssuesEmpty(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printIssues(&buf, nil, 0, nil)
+	assertGolden(t, "issues_empty", buf.Bytes())
+}
+
+func TestPrintIssuesWidth(t *testing.T) {
+	for _, w := range []int{30, 60, 160} {
+		t.Run(strconv.Itoa(w), func(t *testing.T) {
+			setupRenderTest(t, "dark")
+			outputWidth = w
+
+			var buf bytes.Buffer
+			printIssues(&buf, testIssues(), len(testIssues()), nil)
+			assertGolden(t, "issues_width_"+strconv.Itoa(w), buf.Bytes())
+		})
+	}
+}
+
+func TestPrintIssuesColorProfiles(t *testing.T) {
+	for name, p := range colorProfiles {
+		t.Run(name, func(t *testing.T) {
+			setupRenderTest(t, "dark")
+			lipgloss.SetColorProfile(p)
+
+			var buf bytes.Buffer
+			printIssues(&buf, testIssues(), len(testIssues()), nil)
+			assertGolden(t, "issues_profile_"+name, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintPullRequests(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printPullRequests(&buf, testPullRequests(), len(testPullRequests()), nil)
+			assertGolden(t, "pull_requests_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintPullRequestsEmpty(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printPullRequests(&buf, nil, 0, nil)
+	assertGolden(t, "pull_requests_empty", buf.Bytes())
+}
+
+func TestPrintBranches(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			branches, stats := testBranches()
+			printBranches(&buf, branches, stats, nil)
+			assertGolden(t, "branches_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintBranchesWidth(t *testing.T) {
+	for _, w := range []int{50, 70, 80} {
+		t.Run(strconv.Itoa(w), func(t *testing.T) {
+			setupRenderTest(t, "dark")
+			outputWidth = w
+
+			var buf bytes.Buffer
+			branches, stats := testBranches()
+			printBranches(&buf, branches, stats, nil)
+			assertGolden(t, "branches_width_"+strconv.Itoa(w), buf.Bytes())
+		})
+	}
+}
+
+func TestPrintBranchesEmpty(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printBranches(&buf, nil, nil, nil)
+	assertGolden(t, "branches_empty", buf.Bytes())
+}
+
+func TestPrintCommits(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printCommits(&buf, vcs.Repo{
+				LastRelease: vcs.Release{
+					TagName:      "v1.0.0",
+					PublishedAt:  fixedNow.Add(-60 * 24 * time.Hour),
+					CommitsSince: testCommits(),
+					CommitCount:  len(testCommits()),
+				},
+			}, nil)
+			assertGolden(t, "commits_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintCommitsEmpty(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printCommits(&buf, vcs.Repo{}, nil)
+	assertGolden(t, "commits_empty", buf.Bytes())
+}
+
+func TestPrintPipelines(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printPipelines(&buf, testPipelines())
+			assertGolden(t, "pipelines_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintPipelinesEmpty(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	printPipelines(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no CI section, got %q", buf.String())
+	}
+}
+
+func TestPrintBranchPullRequest(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printBranchPullRequest(&buf, testBranchPullRequest())
+			assertGolden(t, "branch_pr_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintIssueDetails(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			if err := printIssueDetails(&buf, testIssueDetails()); err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, "issue_details_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintPullRequestDetails(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	pr := testBranchPullRequest()
+	pr.Author = "muesli"
+	pr.State = "open"
+	d := &issueDetails{
+		Issue: vcs.Issue{
+			ID:        pr.ID,
+			Title:     pr.Title,
+			Author:    pr.Author,
+			State:     pr.State,
+			Labels:    pr.Labels,
+			CreatedAt: pr.CreatedAt,
+		},
+		PullRequest: pr,
+	}
+
+	var buf bytes.Buffer
+	if err := printIssueDetails(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "pr_details_dark", buf.Bytes())
+}
+
+func TestPrintDashboards(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printDashboards(&buf, testDashboards())
+			assertGolden(t, "dashboards_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPrintInboxes(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printInboxes(&buf, testInboxes())
+			assertGolden(t, "inboxes_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestPullRequestStatusText(t *testing.T) {
+	pr := testBranchPullRequest()
+	exp := "changes requested · CI failed · 3 comments · mergeable"
+	if s := pullRequestStatusText(pr); s != exp {
+		t.Errorf("Expected %q, got %q", exp, s)
+	}
+
+	pr = &vcs.PullRequestStatus{Draft: true, Mergeable: vcs.MergeUnknown}
+	exp = "draft · no reviews · No comments · mergeability unknown"
+	if s := pullRequestStatusText(pr); s != exp {
+		t.Errorf("Expected %q, got %q", exp, s)
+	}
+}
+
+func TestPrintMilestones(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			printMilestones(&buf, testMilestones())
+			assertGolden(t, "milestones_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestRepoRelease(t *testing.T) {
+	for _, th := range renderThemes {
+		t.Run(th, func(t *testing.T) {
+			setupRenderTest(t, th)
+
+			var buf bytes.Buffer
+			for _, age := range []time.Duration{2, 100, 200} {
+				repoRelease(&buf, vcs.Repo{
+					Name: "gitty",
+					LastRelease: vcs.Release{
+						TagName:      "v0.7.0",
+						PublishedAt:  fixedNow.Add(-age * 24 * time.Hour),
+						CommitsSince: testCommits(),
+						CommitCount:  len(testCommits()),
+					},
+				}, nil, 0)
+			}
+			assertGolden(t, "release_"+th, buf.Bytes())
+		})
+	}
+}
+
+func TestRepoReleaseWithHost(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	for _, host := range []string{"github.com", "codeberg.org"} {
+		repoRelease(&buf, vcs.Repo{
+			Host: host,
+			Name: "gitty",
+			LastRelease: vcs.Release{
+				TagName:      "v0.7.0",
+				PublishedAt:  fixedNow.Add(-48 * time.Hour),
+				CommitsSince: testCommits(),
+				CommitCount:  len(testCommits()),
+			},
+		}, nil, len("codeberg.org"))
+	}
+	assertGolden(t, "release_hosts", buf.Bytes())
+}
+
+func TestTrackStatRender(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	var buf bytes.Buffer
+	for _, s := range []*trackStat{
+		nil,
+		{},
+		{Ahead: 5},
+		{Behind: 2},
+		{Outdated: true, Ahead: 100, Behind: 100},
+	} {
+		buf.WriteString(s.Render() + "\n")
+	}
+	assertGolden(t, "trackstat", buf.Bytes())
+}
+
+func TestFitColumns(t *testing.T) {
+	var tests = []struct {
+		total    int
+		fixed    int
+		optional []int
+		title    int
+		visible  int
+	}{
+		{100, 5, []int{9, 20}, 66, 2},
+		{60, 5, []int{9, 30}, 46, 1},
+		{30, 5, []int{9, 30}, 25, 0},
+		{10, 5, []int{9}, minTitleWidth, 0},
+		{400, 5, []int{9}, maxTitleWidth, 1},
+	}
+
+	for _, test := range tests {
+		title, visible := fitColumns(test.total, test.fixed, test.optional...)
+		if title != test.title || visible != test.visible {
+			t.Errorf("fitColumns(%d, %d, %v) = %d, %d; expected %d, %d",
+				test.total, test.fixed, test.optional, title, visible, test.title, test.visible)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/gitty/vcs"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// issueDetails is an issue or pull request along with its comments.
+// PullRequest is nil for issues.
+type issueDetails struct {
+	vcs.Issue
+	PullRequest *vcs.PullRequestStatus
+	Comments    []vcs.Comment
+}
+
+// fetchIssueDetails retrieves the issue or pull request with the given number
+// and its comments. Pull requests get looked up if there is no such issue, or
+// right away if pr is set, as GitLab numbers issues and merge requests
+// separately.
+func fetchIssueDetails(client Client, owner, name string, number int, pr bool) (*issueDetails, error) {
+	if !pr {
+		issue, err := client.Issue(owner, name, number)
+		if err == nil {
+			comments, err := client.IssueComments(owner, name, number)
+			if err != nil {
+				return nil, fmt.Errorf("can't fetch comments: %v", err)
+			}
+			return &issueDetails{Issue: *issue, Comments: comments}, nil
+		}
+		if !errors.Is(err, vcs.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	p, err := client.PullRequest(owner, name, number)
+	if errors.Is(err, vcs.ErrNotFound) {
+		if pr {
+			return nil, fmt.Errorf("PR %d not found", number)
+		}
+		return nil, fmt.Errorf("Issue/PR %d not found", number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	comments, err := client.PullRequestComments(owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch comments: %v", err)
+	}
+
+	return &issueDetails{
+		Issue: vcs.Issue{
+			ID:        p.ID,
+			Body:      p.Body,
+			Title:     p.Title,
+			Author:    p.Author,
+			State:     p.State,
+			Labels:    p.Labels,
+			CreatedAt: p.CreatedAt,
+			URL:       p.URL,
+		},
+		PullRequest: p,
+		Comments:    comments,
+	}, nil
+}
+
+// renderMarkdown renders a Markdown text for the terminal, wrapped to the
+// output width.
+func renderMarkdown(s string) (string, error) {
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(theme.markdownStyle),
+		glamour.WithColorProfile(lipgloss.ColorProfile()),
+		glamour.WithWordWrap(outputWidth),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := r.Render(s)
+	if err != nil {
+		return "", fmt.Errorf("can't render markdown: %v", err)
+	}
+	return out, nil
+}
+
+// stateColor returns the color representing the state of an issue or pull
+// request.
+func stateColor(state string) string {
+	switch state {
+	case "open":
+		return theme.colorGreen
+	case "merged":
+		return theme.colorMagenta
+	default:
+		return theme.colorRed
+	}
+}
+
+// printIssueDetails prints an issue or pull request, its description and its
+// comments.
+func printIssueDetails(w io.Writer, d *issueDetails) error {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray))
+	numberStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue))
+	stateStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(stateColor(d.State)))
+
+	icon := "🐛"
+	if d.PullRequest != nil {
+		icon = "📌"
+	}
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s #%d %s", icon, d.ID, d.Title)))
+
+	// state, author, age, labels and milestone
+	parts := []string{stateStyle.Render(d.State)}
+	if d.Author != "" {
+		parts = append(parts, textStyle.Render(fmt.Sprintf("@%s opened this %s", d.Author, relTime(d.CreatedAt))))
+	} else {
+		parts = append(parts, textStyle.Render("opened "+relTime(d.CreatedAt)))
+	}
+	if d.Milestone != "" {
+		parts = append(parts, textStyle.Render("🎯 "+d.Milestone))
+	}
+	if len(d.Labels) > 0 {
+		parts = append(parts, d.Labels.View())
+	}
+	fmt.Fprintln(w, strings.Join(parts, genericStyle.Render(" · ")))
+
+	if pr := d.PullRequest; pr != nil {
+		fmt.Fprintln(w, numberStyle.Render(pr.Branch)+genericStyle.Render(" · ")+renderPullRequestStatus(pr))
+	}
+
+	body := d.Body
+	if strings.TrimSpace(body) == "" {
+		body = "*No description provided.*"
+	}
+	out, err := renderMarkdown(body)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, out)
+
+	if len(d.Comments) == 0 {
+		return nil
+	}
+	commentsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	fmt.Fprintln(w, commentsStyle.Render(fmt.Sprintf("%s %s", "💬", pluralize(len(d.Comments), "comment", "comments"))))
+
+	for _, c := range d.Comments {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, numberStyle.Render("@"+c.Author)+textStyle.Render(" commented "+relTime(c.CreatedAt)))
+
+		out, err := renderMarkdown(c.Body)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, out)
+	}
+
+	return nil
+}
+
+func parseShow(args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	web := fs.Bool("web", false, "Open the issue or pull request in the browser")
+	pr := fs.Bool("pr", false, "Show the pull request with the given number, even if there is an issue with the same number")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gitty show [--web] [--pr] NUMBER")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	num, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || num <= 0 {
+		fmt.Printf("Not an issue or pull request number: %s\n", fs.Arg(0))
+		os.Exit(1)
+	}
+
+	host, owner, name, _, err := parseRepo(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	client, err := guessClient(host)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *web {
+		iu := client.IssueURL(owner, name, num)
+		if *pr {
+			p, err := client.PullRequest(owner, name, num)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			iu = p.URL
+		}
+		if err := open.Start(iu); err != nil {
+			fmt.Println("URL:", iu)
+		}
+		return
+	}
+
+	d, err := fetchIssueDetails(client, owner, name, num, *pr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := printIssueDetails(os.Stdout, d); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// showClient answers issue and pull request lookups locally.
+type showClient struct {
+	Client
+	issueErr error
+	pr       *vcs.PullRequestStatus
+}
+
+func (c showClient) Issue(owner string, name string, number int) (*vcs.Issue, error) {
+	if c.issueErr != nil {
+		return nil, c.issueErr
+	}
+	return &vcs.Issue{ID: number, Title: "Issue"}, nil
+}
+
+func (c showClient) IssueComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	return nil, nil
+}
+
+func (c showClient) PullRequest(owner string, name string, number int) (*vcs.PullRequestStatus, error) {
+	if c.pr == nil {
+		return nil, fmt.Errorf("pull request %d %w", number, vcs.ErrNotFound)
+	}
+	return c.pr, nil
+}
+
+func (c showClient) PullRequestComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	return nil, nil
+}
+
+func TestFetchIssueDetails(t *testing.T) {
+	pr := &vcs.PullRequestStatus{PullRequest: vcs.PullRequest{ID: 42, Title: "Pull request"}}
+	notFound := fmt.Errorf("issue 42 %w", vcs.ErrNotFound)
+
+	tt := []struct {
+		name     string
+		client   showClient
+		pr       bool
+		expTitle string
+		expErr   string
+	}{
+		{"issue", showClient{pr: pr}, false, "Issue", ""},
+		{"pull request", showClient{issueErr: notFound, pr: pr}, false, "Pull request", ""},
+		{"pull request with issue of same number", showClient{pr: pr}, true, "Pull request", ""},
+		{"neither", showClient{issueErr: notFound}, false, "", "Issue/PR 42 not found"},
+		{"no pull request", showClient{}, true, "", "PR 42 not found"},
+		{"other errors", showClient{issueErr: errors.New("rate limited"), pr: pr}, false, "", "rate limited"},
+	}
+
+	for _, test := range tt {
+		d, err := fetchIssueDetails(test.client, "muesli", "gitty", 42, test.pr)
+		if test.expErr != "" {
+			if err == nil || err.Error() != test.expErr {
+				t.Errorf("%s: expected error %q, got %v", test.name, test.expErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if d.Title != test.expTitle {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expTitle, d.Title)
+		}
+	}
+}
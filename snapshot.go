@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// snapshot records what gitty showed for a repository during its last run.
+type snapshot struct {
+	Time         time.Time         `json:"time"`
+	Issues       []int             `json:"issues"`
+	PullRequests []int             `json:"pullRequests"`
+	Branches     map[string]string `json:"branches"`
+	Commits      []string          `json:"commits"`
+
+	// the newest issue and pull request ever shown
+	NewestIssue       listMark `json:"newestIssue"`
+	NewestPullRequest listMark `json:"newestPullRequest"`
+}
+
+// Overviews and release reports keep separate snapshots, so one doesn't move
+// the other's last run.
+const (
+	overviewSnapshots = "snapshots"
+	releaseSnapshots  = "releases"
+)
+
+// snapshotPath returns where the snapshot of a repository is stored.
+func snapshotPath(kind, host, nameWithOwner string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gitty", kind, host,
+		filepath.FromSlash(nameWithOwner)+".json"), nil
+}
+
+// loadSnapshot returns the snapshot of a repository's last run, or nil if
+// there is none.
+func loadSnapshot(kind, host, nameWithOwner string) *snapshot {
+	path, err := snapshotPath(kind, host, nameWithOwner)
+	if err != nil {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var s snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+func (s *snapshot) save(kind, host, nameWithOwner string) error {
+	path, err := snapshotPath(kind, host, nameWithOwner)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+// update records the current state of an overview in the snapshot.
+func (s *snapshot) update(o *Overview) {
+	s.Time = now()
+	s.Issues = nil
+	for _, v := range o.Issues {
+		s.Issues = append(s.Issues, v.ID)
+		s.NewestIssue.add(v.ID, v.CreatedAt)
+	}
+	s.PullRequests = nil
+	for _, v := range o.PullRequests {
+		s.PullRequests = append(s.PullRequests, v.ID)
+		s.NewestPullRequest.add(v.ID, v.CreatedAt)
+	}
+	s.Branches = map[string]string{}
+	for _, v := range o.Branches {
+		s.Branches[v.Name] = v.LastCommit.ID
+	}
+	s.updateCommits(o.Commits)
+}
+
+func (s *snapshot) updateCommits(commits []vcs.Commit) {
+	s.Time = now()
+	s.Commits = nil
+	for _, v := range commits {
+		s.Commits = append(s.Commits, v.ID)
+	}
+}
+
+// overview returns the snapshot as an Overview that can be compared with the
+// current state using diffOverview.
+func (s *snapshot) overview() *Overview {
+	o := &Overview{}
+	for _, v := range s.Issues {
+		o.Issues = append(o.Issues, vcs.Issue{ID: v})
+	}
+	for _, v := range s.PullRequests {
+		o.PullRequests = append(o.PullRequests, vcs.PullRequest{ID: v})
+	}
+	for k, v := range s.Branches {
+		o.Branches = append(o.Branches, vcs.Branch{Name: k, LastCommit: vcs.Commit{ID: v}})
+	}
+	o.Commits = s.commits()
+	return o
+}
+
+func (s *snapshot) commits() []vcs.Commit {
+	var commits []vcs.Commit //nolint
+	for _, v := range s.Commits {
+		commits = append(commits, vcs.Commit{ID: v})
+	}
+	return commits
+}
+
+// compareWithLastRun marks everything in the overview that changed since the
+// last run and records the current state for the next run.
+func compareWithLastRun(o *Overview) {
+	key := o.Owner + "/" + o.Name
+
+	s := loadSnapshot(overviewSnapshots, o.Host, key)
+	if s != nil {
+		o.changes = diffOverview(s.overview(), o, s.NewestIssue, s.NewestPullRequest)
+		o.lastRun = s.Time
+	} else {
+		s = &snapshot{}
+	}
+
+	// record the full state before we filter the overview
+	s.update(o)
+	if *sinceLastRun && o.changes != nil {
+		o.onlyChanges()
+	}
+
+	if err := s.save(overviewSnapshots, o.Host, key); err != nil {
+		fmt.Fprintf(os.Stderr, "Can't save snapshot: %s\n", err)
+	}
+}
+
+// compareReleaseWithLastRun returns the commits of a repository's release
+// report that are new since the last run, and records the current state.
+func compareReleaseWithLastRun(host string, repo *vcs.Repo) *changeSet {
+	var changes *changeSet
+
+	s := loadSnapshot(releaseSnapshots, host, repo.NameWithOwner)
+	if s != nil {
+		changes = newChangeSet()
+		changes.addNewCommits(s.commits(), repo.LastRelease.CommitsSince)
+	} else {
+		s = &snapshot{}
+	}
+
+	s.updateCommits(repo.LastRelease.CommitsSince)
+	if err := s.save(releaseSnapshots, host, repo.NameWithOwner); err != nil {
+		fmt.Fprintf(os.Stderr, "Can't save snapshot: %s\n", err)
+	}
+
+	if *sinceLastRun && changes != nil {
+		repo.LastRelease.CommitsSince = onlyNewCommits(repo.LastRelease.CommitsSince, changes)
+		repo.LastRelease.CommitCount = len(repo.LastRelease.CommitsSince)
+	}
+	return changes
+}
+
+// onlyChanges removes all items from the overview that haven't changed.
+func (o *Overview) onlyChanges() {
+	var issues []vcs.Issue
+	for _, v := range o.Issues {
+		if o.changes.issue(v.ID) {
+			issues = append(issues, v)
+		}
+	}
+	o.Issues = issues
+	o.IssueCount = len(issues)
+
+	var prs []vcs.PullRequest
+	for _, v := range o.PullRequests {
+		if o.changes.pullRequest(v.ID) {
+			prs = append(prs, v)
+		}
+	}
+	o.PullRequests = prs
+	o.PullRequestCount = len(prs)
+
+	var branches []vcs.Branch
+	for _, v := range o.Branches {
+		if o.changes.branch(v.Name) {
+			branches = append(branches, v)
+		}
+	}
+	o.Branches = branches
+
+	o.Commits = onlyNewCommits(o.Commits, o.changes)
+	o.Repo.LastRelease.CommitsSince = o.Commits
+	o.Repo.LastRelease.CommitCount = len(o.Commits)
+}
+
+func onlyNewCommits(commits []vcs.Commit, changes *changeSet) []vcs.Commit {
+	var c []vcs.Commit
+	for _, v := range commits {
+		if changes.commit(v.ID) {
+			c = append(c, v)
+		}
+	}
+	return c
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func setCacheDir(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func TestCompareWithLastRun(t *testing.T) {
+	setCacheDir(t)
+
+	o := testOverview()
+	compareWithLastRun(o)
+	if o.changes != nil {
+		t.Fatalf("Expected no changes on first run, got %+v", o.changes)
+	}
+
+	o = testOverview()
+	o.Issues = append(o.Issues, vcs.Issue{ID: 99, Title: "New issue", CreatedAt: fixedNow})
+	compareWithLastRun(o)
+	if !o.changes.issue(99) || o.changes.issue(56) {
+		t.Errorf("Unexpected issue changes: %v", o.changes.issues)
+	}
+	if len(o.changes.commits) != 0 {
+		t.Errorf("Unexpected commit changes: %v", o.changes.commits)
+	}
+
+	// the new issue was recorded by the previous run
+	o = testOverview()
+	o.Issues = append(o.Issues, vcs.Issue{ID: 99, Title: "New issue", CreatedAt: fixedNow})
+	compareWithLastRun(o)
+	if o.changes.issue(99) {
+		t.Error("Issue should not be new anymore")
+	}
+
+	// closing the newest issue moves an older one into the list, which
+	// isn't new
+	o = testOverview()
+	o.Issues = append(o.Issues[1:], vcs.Issue{ID: 3, Title: "Old issue", CreatedAt: fixedNow.Add(-30 * 24 * time.Hour)})
+	compareWithLastRun(o)
+	if o.changes.issue(3) {
+		t.Error("An older issue moving into the list should not be new")
+	}
+}
+
+func TestSinceLastRun(t *testing.T) {
+	setCacheDir(t)
+	prev := *sinceLastRun
+	*sinceLastRun = true
+	t.Cleanup(func() { *sinceLastRun = prev })
+
+	compareWithLastRun(testOverview())
+
+	o := testOverview()
+	o.PullRequests = append(o.PullRequests, vcs.PullRequest{ID: 100, Title: "New PR", CreatedAt: fixedNow})
+	compareWithLastRun(o)
+	if len(o.Issues) != 0 || len(o.Branches) != 0 || len(o.Commits) != 0 {
+		t.Errorf("Expected only changes, got %d issues, %d branches, %d commits",
+			len(o.Issues), len(o.Branches), len(o.Commits))
+	}
+	if len(o.PullRequests) != 1 || o.PullRequests[0].ID != 100 {
+		t.Errorf("Expected only the new pull request, got %v", o.PullRequests)
+	}
+
+}
+
+func TestCompareReleaseWithLastRun(t *testing.T) {
+	setCacheDir(t)
+	prev := *sinceLastRun
+	*sinceLastRun = true
+	t.Cleanup(func() { *sinceLastRun = prev })
+
+	repo := testOverview().Repo
+	if c := compareReleaseWithLastRun("github.com", &repo); c != nil {
+		t.Errorf("Expected no changes on first run, got %v", c)
+	}
+	if len(repo.LastRelease.CommitsSince) != 2 {
+		t.Errorf("Expected all commits on first run, got %v", repo.LastRelease.CommitsSince)
+	}
+
+	repo = testOverview().Repo
+	repo.LastRelease.CommitsSince = append([]vcs.Commit{{ID: "abcdef"}}, repo.LastRelease.CommitsSince...)
+	c := compareReleaseWithLastRun("github.com", &repo)
+	if !c.commit("abcdef") || len(repo.LastRelease.CommitsSince) != 1 {
+		t.Errorf("Expected only the new commit, got %v", repo.LastRelease.CommitsSince)
+	}
+}
+
+// release reports don't move the last run of the overview
+func TestReleaseReportKeepsOverviewSnapshot(t *testing.T) {
+	setCacheDir(t)
+
+	o := testOverview()
+	compareWithLastRun(o)
+	before := loadSnapshot(overviewSnapshots, o.Host, o.Owner+"/"+o.Name)
+
+	repo := testOverview().Repo
+	repo.LastRelease.CommitsSince = []vcs.Commit{{ID: "abcdef"}}
+	compareReleaseWithLastRun(o.Host, &repo)
+
+	after := loadSnapshot(overviewSnapshots, o.Host, o.Owner+"/"+o.Name)
+	if !after.Time.Equal(before.Time) || len(after.Commits) != len(before.Commits) {
+		t.Errorf("Expected the overview snapshot to be unchanged, got %+v", after)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"text/template"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+// templateFuncs returns the helper functions available in output templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"ago":            ago,
+		"since":          relTime,
+		"pluralize":      pluralize,
+		"pluralizeTotal": pluralizeTotal,
+		"truncate":       truncateString,
+		"head":           head,
+		"label": func(l vcs.Label) string {
+			return l.View()
+		},
+		"labels": func(ll vcs.Labels) string {
+			return ll.View()
+		},
+		"trackStat": func(s *trackStat) string {
+			return s.Render()
+		},
+	}
+}
+
+// head returns the first n elements of a slice. Like gitty's --max flags, a
+// value of 0 or less means no limit.
+func head(n int, list interface{}) (interface{}, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't take head of %s", v.Kind())
+	}
+
+	if n <= 0 || n > v.Len() {
+		n = v.Len()
+	}
+	return v.Slice(0, n).Interface(), nil
+}
+
+// renderTemplate executes the text/template in path with data.
+func renderTemplate(w io.Writer, path string, data interface{}) error {
+	tmpl, err := template.New(filepath.Base(path)).
+		Funcs(templateFuncs()).
+		ParseFiles(path)
+	if err != nil {
+		return fmt.Errorf("can't parse template: %v", err)
+	}
+
+	return tmpl.Execute(w, data)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	setupRenderTest(t, "dark")
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	var buf bytes.Buffer
+	if err := renderTemplate(&buf, filepath.Join("testdata", "status.tmpl"), testOverview()); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "template_status", buf.Bytes())
+}
+
+func TestHead(t *testing.T) {
+	l := []int{1, 2, 3}
+
+	for n, exp := range map[int]int{0: 3, 2: 2, 5: 3, -1: 3} {
+		v, err := head(n, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v.([]int)) != exp {
+			t.Errorf("head(%d) returned %d elements, expected %d", n, len(v.([]int)), exp)
+		}
+	}
+
+	if _, err := head(1, "foo"); err == nil {
+		t.Error("Expected error for non-slice argument")
+	}
+}
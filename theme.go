@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 )
 
@@ -16,19 +20,34 @@ type Theme struct {
 	colorGray     string
 	colorMagenta  string
 	colorCyan     string
-}
 
-func defaultThemeName() string {
-	if !termenv.HasDarkBackground() {
-		return "light"
-	}
-	return "dark"
+	// glamour style used to render Markdown
+	markdownStyle string
+
+	// monochrome themes disable all colors, including label colors
+	monochrome bool
 }
 
-func initTheme() {
-	themes := make(map[string]Theme)
+// ThemeConfig defines a custom theme in the config file. Colors can either be
+// hex values or ANSI color numbers. Colors that aren't set are inherited from
+// the Base theme.
+type ThemeConfig struct {
+	Base       string `json:"base"`
+	Black      string `json:"black"`
+	Red        string `json:"red"`
+	Yellow     string `json:"yellow"`
+	Green      string `json:"green"`
+	Blue       string `json:"blue"`
+	Tooltip    string `json:"tooltip"`
+	DarkGray   string `json:"darkGray"`
+	Gray       string `json:"gray"`
+	Magenta    string `json:"magenta"`
+	Cyan       string `json:"cyan"`
+	Monochrome bool   `json:"monochrome"`
+}
 
-	themes["dark"] = Theme{
+var themes = map[string]Theme{
+	"dark": {
 		colorBlack:    "#222222",
 		colorRed:      "#E88388",
 		colorYellow:   "#DBAB79",
@@ -39,9 +58,10 @@ func initTheme() {
 		colorGray:     "#B9BFCA",
 		colorMagenta:  "#D290E4",
 		colorCyan:     "#66C2CD",
-	}
+		markdownStyle: "dark",
+	},
 
-	themes["light"] = Theme{
+	"light": {
 		colorBlack:    "#eeeeee",
 		colorRed:      "#D70000",
 		colorYellow:   "#FFAF00",
@@ -52,6 +72,135 @@ func initTheme() {
 		colorGray:     "#303030",
 		colorMagenta:  "#AF00FF",
 		colorCyan:     "#0087FF",
+		markdownStyle: "light",
+	},
+
+	"high-contrast": {
+		colorBlack:    "#000000",
+		colorRed:      "#FF5F5F",
+		colorYellow:   "#FFFF5F",
+		colorGreen:    "#5FFF5F",
+		colorBlue:     "#5FAFFF",
+		colorDarkGray: "#FFFFFF",
+		colorTooltip:  "#BCBCBC",
+		colorGray:     "#FFFFFF",
+		colorMagenta:  "#FF5FFF",
+		colorCyan:     "#5FFFFF",
+		markdownStyle: "dark",
+	},
+
+	"monochrome": {
+		markdownStyle: "notty",
+		monochrome:    true,
+	},
+}
+
+// colorProfiles maps the values of the --color flag to termenv profiles.
+var colorProfiles = map[string]termenv.Profile{
+	"truecolor": termenv.TrueColor,
+	"256":       termenv.ANSI256,
+	"16":        termenv.ANSI,
+	"none":      termenv.Ascii,
+}
+
+func defaultThemeName() string {
+	// don't query the terminal if we're not going to print colors anyway
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		return "dark"
+	}
+	if !termenv.HasDarkBackground() {
+		return "light"
+	}
+	return "dark"
+}
+
+// theme returns the Theme defined by the config.
+func (tc ThemeConfig) theme() (Theme, error) {
+	base := tc.Base
+	if base == "" {
+		base = "dark"
+	}
+	t, ok := themes[base]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown base theme: %s", base)
+	}
+
+	for _, c := range []struct {
+		dst *string
+		src string
+	}{
+		{&t.colorBlack, tc.Black},
+		{&t.colorRed, tc.Red},
+		{&t.colorYellow, tc.Yellow},
+		{&t.colorGreen, tc.Green},
+		{&t.colorBlue, tc.Blue},
+		{&t.colorTooltip, tc.Tooltip},
+		{&t.colorDarkGray, tc.DarkGray},
+		{&t.colorGray, tc.Gray},
+		{&t.colorMagenta, tc.Magenta},
+		{&t.colorCyan, tc.Cyan},
+	} {
+		if c.src != "" {
+			*c.dst = c.src
+		}
+	}
+	t.monochrome = t.monochrome || tc.Monochrome
+	if t.monochrome {
+		t.markdownStyle = "notty"
+	}
+
+	return t, nil
+}
+
+func initColorProfile() error {
+	if *colorProfile == "" || *colorProfile == "auto" {
+		// lipgloss already honors NO_COLOR and CLICOLOR_FORCE
+		return nil
+	}
+
+	p, ok := colorProfiles[strings.ToLower(*colorProfile)]
+	if !ok {
+		return fmt.Errorf("unknown color profile: %s", *colorProfile)
+	}
+	lipgloss.SetColorProfile(p)
+
+	return nil
+}
+
+func initTheme() error {
+	// custom themes can only be based on built-in themes
+	custom := make(map[string]Theme, len(config.Themes))
+	for name, tc := range config.Themes {
+		if _, ok := themes[name]; ok {
+			return fmt.Errorf("theme %s is a built-in theme", name)
+		}
+
+		t, err := tc.theme()
+		if err != nil {
+			return fmt.Errorf("can't load theme %s: %v", name, err)
+		}
+		custom[name] = t
+	}
+	for name, t := range custom {
+		themes[name] = t
+	}
+
+	name := *themeName
+	if name == "" {
+		name = config.Theme
+	}
+	if name == "" {
+		name = defaultThemeName()
+	}
+
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s", name)
+	}
+	theme = t
+
+	if theme.monochrome {
+		lipgloss.SetColorProfile(termenv.Ascii)
 	}
-	theme = themes[defaultThemeName()]
+	return nil
 }
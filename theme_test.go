@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestThemeConfig(t *testing.T) {
+	tc := ThemeConfig{
+		Base: "light",
+		Red:  "#FF0000",
+		Blue: "21",
+	}
+
+	th, err := tc.theme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.colorRed != "#FF0000" || th.colorBlue != "21" {
+		t.Errorf("Colors not applied: %+v", th)
+	}
+	if th.colorGreen != themes["light"].colorGreen {
+		t.Errorf("Color not inherited from base theme: %s", th.colorGreen)
+	}
+
+	if _, err := (ThemeConfig{Base: "nope"}).theme(); err == nil {
+		t.Error("Expected error for unknown base theme")
+	}
+}
@@ -2,14 +2,35 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
+	"github.com/muesli/gitty/vcs"
+	"github.com/muesli/reflow/truncate"
 )
 
+// now returns the current time. It can be replaced to render with a fixed clock.
+var now = time.Now
+
+// debugf prints a diagnostic message to stderr if the GITTY_DEBUG env var is
+// set.
+func debugf(format string, args ...interface{}) {
+	if os.Getenv("GITTY_DEBUG") == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// relTime returns a human-readable representation of the time passed since t.
+func relTime(t time.Time) string {
+	return humanize.RelTime(t, now(), "ago", "from now")
+}
+
 func ago(t time.Time) string {
-	s := humanize.Time(t)
+	s := relTime(t)
 	if strings.Contains(s, "minute") || strings.Contains(s, "second") {
 		return "now"
 	}
@@ -39,3 +60,22 @@ func pluralize(count int, singular string, plural string) string {
 		return fmt.Sprintf("%d %s", count, plural)
 	}
 }
+
+// pluralizeTotal is pluralize for a total amount reported by an API. Unknown
+// totals are shown as at least the amount of retrieved items.
+func pluralizeTotal(total int, retrieved int, singular string, plural string) string {
+	if total == vcs.UnknownTotal {
+		return fmt.Sprintf("%d+ %s", retrieved, plural)
+	}
+	return pluralize(total, singular, plural)
+}
+
+// truncateString truncates s to the given cell width, appending an ellipsis
+// if it had to be shortened.
+func truncateString(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+
+	return truncate.StringWithTail(s, uint(width), "…")
+}
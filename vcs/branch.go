@@ -4,4 +4,5 @@ package vcs
 type Branch struct {
 	Name       string
 	LastCommit Commit
+	URL        string
 }
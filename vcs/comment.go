@@ -0,0 +1,13 @@
+package vcs
+
+import (
+	"time"
+)
+
+// Comment represents a comment on an issue or pull request.
+type Comment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	URL       string
+}
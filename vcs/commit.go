@@ -10,4 +10,5 @@ type Commit struct {
 	MessageHeadline string
 	CommittedAt     time.Time
 	Author          string
+	URL             string
 }
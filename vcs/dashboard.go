@@ -0,0 +1,22 @@
+package vcs
+
+// AssignedIssue is an issue along with the repository it belongs to.
+type AssignedIssue struct {
+	Issue
+	Repo string
+}
+
+// UserPullRequest is a pull request along with the repository it belongs to.
+type UserPullRequest struct {
+	PullRequestStatus
+	Repo string
+}
+
+// Dashboard is the open work of a user across all repositories of a host:
+// issues assigned to them, pull requests awaiting their review, and their own
+// pull requests.
+type Dashboard struct {
+	Assigned       []AssignedIssue
+	ReviewRequests []UserPullRequest
+	Authored       []UserPullRequest
+}
@@ -0,0 +1,116 @@
+package gitea
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/muesli/gitty/vcs"
+)
+
+// labelIDs looks up the IDs of the given labels.
+func (c *Client) labelIDs(owner, name string, labels []string) ([]int64, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	var all []*gitea.Label
+	for page := 1; ; page++ {
+		ll, _, err := c.api.ListRepoLabels(owner, name, gitea.ListLabelsOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(ll) == 0 {
+			break
+		}
+		all = append(all, ll...)
+	}
+
+	var ids []int64
+	for _, l := range labels {
+		var found bool
+		for _, v := range all {
+			if strings.EqualFold(v.Name, l) {
+				ids = append(ids, v.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown label: %s", l)
+		}
+	}
+	return ids, nil
+}
+
+// milestoneID looks up the ID of the milestone with the given title.
+func (c *Client) milestoneID(owner, name string, title string) (int64, error) {
+	if title == "" {
+		return 0, nil
+	}
+
+	m, _, err := c.api.GetMilestoneByName(owner, name, title)
+	if err != nil {
+		return 0, fmt.Errorf("unknown milestone %s: %v", title, err)
+	}
+	return m.ID, nil
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(owner string, name string, issue vcs.NewIssue) (*vcs.Issue, error) {
+	labels, err := c.labelIDs(owner, name, issue.Labels)
+	if err != nil {
+		return nil, err
+	}
+	milestone, err := c.milestoneID(owner, name, issue.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := c.api.CreateIssue(owner, name, gitea.CreateIssueOption{
+		Title:     issue.Title,
+		Body:      issue.Body,
+		Assignees: issue.Assignees,
+		Milestone: milestone,
+		Labels:    labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i := issueFromAPI(v)
+	return &i, nil
+}
+
+// CreatePullRequest creates a new pull request.
+func (c *Client) CreatePullRequest(owner string, name string, pr vcs.NewPullRequest) (*vcs.PullRequest, error) {
+	labels, err := c.labelIDs(owner, name, pr.Labels)
+	if err != nil {
+		return nil, err
+	}
+	milestone, err := c.milestoneID(owner, name, pr.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := c.api.CreatePullRequest(owner, name, gitea.CreatePullRequestOption{
+		Head:      pr.Head,
+		Base:      pr.Base,
+		Title:     pr.Title,
+		Body:      pr.Body,
+		Assignees: pr.Assignees,
+		Milestone: milestone,
+		Labels:    labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := pullRequestFromAPI(v)
+	return &p, nil
+}
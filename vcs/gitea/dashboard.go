@@ -0,0 +1,85 @@
+package gitea
+
+import (
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/muesli/gitty/vcs"
+)
+
+// maxDashboardPullRequests caps the pull requests listed per dashboard
+// section, as the status of each of them costs three more requests.
+const maxDashboardPullRequests = 20
+
+// searchIssues searches the open issues or pull requests related to the
+// authenticated user, e.g. assigned to or created by them. The SDK's search
+// doesn't support these filters.
+func (c *Client) searchIssues(kind string, filter string, limit int) ([]*gitea.Issue, error) {
+	var issues []*gitea.Issue
+	path := fmt.Sprintf("/repos/issues/search?type=%s&state=open&%s=true&limit=%d", kind, filter, limit)
+	if err := c.get(path, &issues); err != nil {
+		return nil, fmt.Errorf("can't search %s: %v", kind, err)
+	}
+	return issues, nil
+}
+
+// userPullRequests fetches the status of pull requests found by a search.
+// Pull requests whose status can't be retrieved are left out.
+func (c *Client) userPullRequests(issues []*gitea.Issue) ([]vcs.UserPullRequest, error) {
+	var prs []vcs.UserPullRequest
+	for _, v := range issues {
+		if v.Repository == nil {
+			continue
+		}
+		owner, name := v.Repository.Owner, v.Repository.Name
+		// e.g. the repository became inaccessible
+		pr, _, err := c.api.GetPullRequest(owner, name, v.Index)
+		if err != nil {
+			continue
+		}
+		p, err := c.pullRequestStatus(owner, name, pr)
+		if err != nil {
+			continue
+		}
+		prs = append(prs, vcs.UserPullRequest{
+			PullRequestStatus: *p,
+			Repo:              v.Repository.FullName,
+		})
+	}
+	return prs, nil
+}
+
+// Dashboard returns the open issues assigned to the authenticated user, the
+// pull requests awaiting their review, and their own open pull requests.
+// Gitea can only search these for the authenticated user, so username is
+// ignored.
+func (c *Client) Dashboard(username string) (vcs.Dashboard, error) {
+	var d vcs.Dashboard
+
+	issues, err := c.searchIssues("issues", "assigned", 50)
+	if err != nil {
+		return d, err
+	}
+	for _, v := range issues {
+		a := vcs.AssignedIssue{Issue: issueFromAPI(v)}
+		if v.Repository != nil {
+			a.Repo = v.Repository.FullName
+		}
+		d.Assigned = append(d.Assigned, a)
+	}
+
+	requested, err := c.searchIssues("pulls", "review_requested", maxDashboardPullRequests)
+	if err != nil {
+		return d, err
+	}
+	if d.ReviewRequests, err = c.userPullRequests(requested); err != nil {
+		return d, err
+	}
+
+	authored, err := c.searchIssues("pulls", "created", maxDashboardPullRequests)
+	if err != nil {
+		return d, err
+	}
+	d.Authored, err = c.userPullRequests(authored)
+	return d, err
+}
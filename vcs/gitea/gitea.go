@@ -1,8 +1,11 @@
 package gitea
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,8 +15,10 @@ import (
 
 // Client is a gitea client.
 type Client struct {
-	api  *gitea.Client
-	host string
+	api       *gitea.Client
+	transport *vcs.RateLimitTransport
+	host      string
+	token     string
 }
 
 // NewClient returns a new gitea client.
@@ -24,7 +29,10 @@ func NewClient(baseURL, token string, preverified bool) (*Client, error) {
 	}
 	u.Scheme = "https"
 
-	client, err := gitea.NewClient(u.String(), gitea.SetToken(token))
+	transport := vcs.NewRateLimitTransport(nil)
+	client, err := gitea.NewClient(u.String(),
+		gitea.SetToken(token),
+		gitea.SetHTTPClient(&http.Client{Transport: transport}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %v", err)
 	}
@@ -37,11 +45,44 @@ func NewClient(baseURL, token string, preverified bool) (*Client, error) {
 	}
 
 	return &Client{
-		api:  client,
-		host: baseURL,
+		api:       client,
+		transport: transport,
+		host:      baseURL,
+		token:     token,
 	}, nil
 }
 
+// get sends a GET request to an API endpoint the SDK doesn't support and
+// decodes its response into v.
+func (c *Client) get(path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/api/v1%s", c.host, path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+
+	resp, err := (&http.Client{Transport: c.transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// RateLimit returns the current API quota. Most Gitea instances don't limit
+// API requests at all.
+func (c *Client) RateLimit() (vcs.RateLimit, error) {
+	if _, _, err := c.api.ServerVersion(); err != nil {
+		return vcs.RateLimit{}, err
+	}
+
+	return c.transport.RateLimit(), nil
+}
+
 // GetUsername returns the username of the authenticated user.
 func (c *Client) GetUsername() (string, error) {
 	u, _, err := c.api.GetMyUserInfo()
@@ -52,86 +93,92 @@ func (c *Client) GetUsername() (string, error) {
 	return u.UserName, nil
 }
 
-// Issues returns a list of issues for the given repository.
-func (c *Client) Issues(owner string, name string) ([]vcs.Issue, error) {
+// Issues returns a list of issues for the given repository and their total
+// amount. Gitea always lists the most recently created issues first.
+func (c *Client) Issues(owner string, name string, opts vcs.ListOptions) ([]vcs.Issue, int, error) {
 	var i []vcs.Issue
+	var total int
+
+	listOpts := gitea.ListIssueOption{
+		ListOptions: gitea.ListOptions{
+			Page:     1,
+			PageSize: opts.PerPage(50),
+		},
+		State: gitea.StateOpen,
+		Type:  gitea.IssueTypeIssue,
+	}
+	if opts.Milestone != "" {
+		listOpts.Milestones = []string{opts.Milestone}
+	}
 
-	page := 1
 	for {
-		issues, _, err := c.api.ListRepoIssues(owner, name, gitea.ListIssueOption{
-			ListOptions: gitea.ListOptions{
-				Page:     page,
-				PageSize: 250,
-			},
-			State: gitea.StateOpen,
-		})
+		issues, resp, err := c.api.ListRepoIssues(owner, name, listOpts)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		total = totalCount(resp)
 
 		for _, v := range issues {
-			issue := vcs.Issue{
-				ID:        int(v.ID),
-				Title:     v.Title,
-				CreatedAt: v.Created,
-			}
-			for _, l := range v.Labels {
-				issue.Labels = append(issue.Labels, vcs.Label{
-					Name:  l.Name,
-					Color: "#" + l.Color,
-				})
-			}
+			issue := issueFromAPI(v)
 			i = append(i, issue)
 		}
 
-		page++
-		if len(issues) == 0 {
+		listOpts.Page++
+		if len(issues) == 0 || opts.Done(len(i)) {
 			break
 		}
 	}
 
-	return i, nil
+	// without a total, a full last page may be followed by more
+	more := opts.Done(len(i))
+	if more {
+		i = i[:opts.Limit]
+	}
+	return i, vcs.Total(total, len(i), more), nil
 }
 
-// PullRequests returns a list of pull requests for the given repository.
-func (c *Client) PullRequests(owner string, name string) ([]vcs.PullRequest, error) {
+// PullRequests returns a list of pull requests for the given repository and
+// their total amount.
+func (c *Client) PullRequests(owner string, name string, opts vcs.ListOptions) ([]vcs.PullRequest, int, error) {
 	var i []vcs.PullRequest
+	var total int
+
+	order := "newest"
+	if opts.Sort == vcs.SortUpdated {
+		order = "recentupdate"
+	}
 
 	page := 1
 	for {
-		prs, _, err := c.api.ListRepoPullRequests(owner, name, gitea.ListPullRequestsOptions{
+		prs, resp, err := c.api.ListRepoPullRequests(owner, name, gitea.ListPullRequestsOptions{
 			ListOptions: gitea.ListOptions{
 				Page:     page,
-				PageSize: 250,
+				PageSize: opts.PerPage(50),
 			},
 			State: gitea.StateOpen,
+			Sort:  order,
 		})
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		total = totalCount(resp)
 
 		for _, v := range prs {
-			pr := vcs.PullRequest{
-				ID:        int(v.ID),
-				Title:     v.Title,
-				CreatedAt: *v.Created,
-			}
-			for _, l := range v.Labels {
-				pr.Labels = append(pr.Labels, vcs.Label{
-					Name:  l.Name,
-					Color: "#" + l.Color,
-				})
-			}
-			i = append(i, pr)
+			i = append(i, pullRequestFromAPI(v))
 		}
 
 		page++
-		if len(prs) == 0 {
+		if len(prs) == 0 || opts.Done(len(i)) {
 			break
 		}
 	}
 
-	return i, nil
+	// without a total, a full last page may be followed by more
+	more := opts.Done(len(i))
+	if more {
+		i = i[:opts.Limit]
+	}
+	return i, vcs.Total(total, len(i), more), nil
 }
 
 // Repository returns the repository with the given name.
@@ -196,17 +243,18 @@ func (c *Client) Repositories(owner string) ([]vcs.Repo, error) {
 	return repos, nil //nolint
 }
 
-// Branches returns a list of branches for the given repository.
-func (c *Client) Branches(owner string, name string) ([]vcs.Branch, error) {
+// Branches returns a list of branches for the given repository. Gitea can't
+// sort branches, so all of them get retrieved and sorted locally.
+func (c *Client) Branches(owner string, name string, opts vcs.ListOptions) ([]vcs.Branch, error) {
 	var i []vcs.Branch
-	opts := gitea.ListRepoBranchesOptions{
+	lo := gitea.ListRepoBranchesOptions{
 		ListOptions: gitea.ListOptions{
-			PageSize: 250,
+			PageSize: 50,
 		},
 	}
 	for {
-		opts.Page++
-		branches, _, err := c.api.ListRepoBranches(owner, name, opts)
+		lo.Page++
+		branches, _, err := c.api.ListRepoBranches(owner, name, lo)
 		if err != nil {
 			return nil, err
 		}
@@ -222,12 +270,22 @@ func (c *Client) Branches(owner string, name string) ([]vcs.Branch, error) {
 					MessageHeadline: trimMessage(v.Commit.Message),
 					CommittedAt:     v.Commit.Timestamp,
 					Author:          v.Commit.Author.UserName,
+					URL:             v.Commit.URL,
 				},
+				URL: c.BranchURL(owner, name, v.Name),
 			}
 			i = append(i, branch)
 		}
 	}
 
+	if opts.Sort == vcs.SortUpdated {
+		sort.SliceStable(i, func(a, b int) bool {
+			return i[a].LastCommit.CommittedAt.After(i[b].LastCommit.CommittedAt)
+		})
+	}
+	if opts.Done(len(i)) {
+		i = i[:opts.Limit]
+	}
 	return i, nil
 }
 
@@ -240,7 +298,7 @@ func (c *Client) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit,
 		opt := gitea.ListCommitOptions{
 			ListOptions: gitea.ListOptions{
 				Page:     page,
-				PageSize: 250,
+				PageSize: vcs.ListOptions{Limit: max}.PerPage(50),
 			},
 		}
 		h, _, err := c.api.ListRepoCommits(repo.Owner, repo.Name, opt)
@@ -259,6 +317,7 @@ func (c *Client) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit,
 				MessageHeadline: trimMessage(v.RepoCommit.Message),
 				CommittedAt:     v.Created,
 				Author:          v.Author.UserName,
+				URL:             v.HTMLURL,
 			})
 		}
 		if brk {
@@ -300,6 +359,7 @@ func (c *Client) repoFromAPI(p *gitea.Repository) vcs.Repo {
 			Name:        r[0].Title,
 			TagName:     r[0].TagName,
 			PublishedAt: r[0].CreatedAt,
+			URL:         r[0].HTMLURL,
 		}
 	}
 
@@ -308,6 +368,7 @@ func (c *Client) repoFromAPI(p *gitea.Repository) vcs.Repo {
 		Name:          p.Name,
 		NameWithOwner: p.FullName,
 		URL:           p.HTMLURL,
+		DefaultBranch: p.DefaultBranch,
 		Description:   p.Description,
 		Stargazers:    p.Stars,
 		Watchers:      p.Watchers,
@@ -325,3 +386,386 @@ func trimMessage(s string) string {
 
 	return s
 }
+
+// Pipelines returns the combined commit status of the given branches, which
+// includes the runs of Gitea and Forgejo Actions.
+func (c *Client) Pipelines(owner string, name string, branches []string) ([]vcs.Pipeline, error) {
+	var pipelines []vcs.Pipeline //nolint
+
+	for _, branch := range branches {
+		s, _, err := c.api.GetCombinedStatus(owner, name, branch)
+		if err != nil {
+			return pipelines, err
+		}
+		if len(s.Statuses) == 0 {
+			continue
+		}
+
+		var runs []vcs.Pipeline
+		for _, v := range s.Statuses {
+			run := vcs.Pipeline{
+				Name:      v.Context,
+				Status:    pipelineStatus(v.State),
+				StartedAt: v.Created,
+				URL:       v.TargetURL,
+			}
+			if run.Status != vcs.PipelinePending {
+				run.FinishedAt = v.Updated
+			}
+			if run.Status == vcs.PipelineFailed {
+				run.FailedJob = v.Context
+			}
+			runs = append(runs, run)
+		}
+
+		pipelines = append(pipelines, vcs.SummarizePipelines(branch, runs))
+	}
+
+	return pipelines, nil
+}
+
+func pipelineStatus(state gitea.StatusState) vcs.PipelineStatus {
+	switch state {
+	case gitea.StatusSuccess, gitea.StatusWarning:
+		return vcs.PipelineSuccess
+	case gitea.StatusError, gitea.StatusFailure:
+		return vcs.PipelineFailed
+	default:
+		return vcs.PipelinePending
+	}
+}
+
+// Milestones returns the open milestones of the given repository.
+func (c *Client) Milestones(owner string, name string) ([]vcs.Milestone, error) {
+	var milestones []vcs.Milestone //nolint
+
+	// Gitea caps pages at 50 items by default
+	for page := 1; ; page++ {
+		ms, _, err := c.api.ListRepoMilestones(owner, name, gitea.ListMilestoneOption{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+			State: gitea.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range ms {
+			m := vcs.Milestone{
+				ID:           int(v.ID),
+				Title:        v.Title,
+				OpenIssues:   v.OpenIssues,
+				ClosedIssues: v.ClosedIssues,
+				URL:          fmt.Sprintf("https://%s/%s/%s/milestone/%d", c.host, owner, name, v.ID),
+			}
+			if v.Deadline != nil {
+				m.DueOn = *v.Deadline
+			}
+			milestones = append(milestones, m)
+		}
+
+		if len(ms) < 50 {
+			break
+		}
+	}
+
+	vcs.SortMilestones(milestones)
+	return milestones, nil
+}
+
+// maxBranchPullRequestPages limits how many pages of open pull requests are
+// searched for the one of a branch.
+const maxBranchPullRequestPages = 4
+
+// PullRequestForBranch returns the newest open pull request of the given
+// branch from a repository owned by one of headOwners, or nil if there is
+// none.
+func (c *Client) PullRequestForBranch(owner string, name string, branch string, headOwners []string) (*vcs.PullRequestStatus, error) {
+	// Gitea can't filter pull requests by their head branch, so only the
+	// most recent ones get searched
+	var pr *gitea.PullRequest
+	for page := 1; pr == nil && page <= maxBranchPullRequestPages; page++ {
+		prs, _, err := c.api.ListRepoPullRequests(owner, name, gitea.ListPullRequestsOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+			State: gitea.StateOpen,
+			Sort:  "newest",
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(prs) == 0 {
+			break
+		}
+
+		for _, v := range prs {
+			// forks may use the same branch name
+			if v.Head != nil && v.Head.Ref == branch &&
+				v.Head.Repository != nil && vcs.OwnedBy(v.Head.Repository.FullName, headOwners) {
+				pr = v
+				break
+			}
+		}
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	return c.pullRequestStatus(owner, name, pr)
+}
+
+// PullRequest returns the pull request with the given number.
+func (c *Client) PullRequest(owner string, name string, number int) (*vcs.PullRequestStatus, error) {
+	pr, resp, err := c.api.GetPullRequest(owner, name, int64(number))
+	if err != nil {
+		return nil, notFound(err, resp, "pull request", number)
+	}
+	return c.pullRequestStatus(owner, name, pr)
+}
+
+func (c *Client) pullRequestStatus(owner string, name string, pr *gitea.PullRequest) (*vcs.PullRequestStatus, error) {
+	p := &vcs.PullRequestStatus{
+		PullRequest: vcs.PullRequest{
+			ID:        int(pr.Index),
+			Body:      pr.Body,
+			Title:     pr.Title,
+			State:     pullRequestState(pr),
+			CreatedAt: *pr.Created,
+			URL:       pr.HTMLURL,
+		},
+		Branch:    pr.Head.Ref,
+		Draft:     isWIP(pr.Title),
+		Comments:  pr.Comments,
+		Mergeable: vcs.MergeConflicting,
+	}
+	if pr.Mergeable {
+		p.Mergeable = vcs.MergeClean
+	}
+	if pr.Poster != nil {
+		p.Author = pr.Poster.UserName
+	}
+	if pr.Head.Repository != nil {
+		p.HeadRepo = pr.Head.Repository.FullName
+		p.HeadCloneURL = pr.Head.Repository.CloneURL
+	}
+	for _, l := range pr.Labels {
+		p.Labels = append(p.Labels, vcs.Label{
+			Name:  l.Name,
+			Color: "#" + l.Color,
+		})
+	}
+
+	reviews, _, err := c.api.ListPullReviews(owner, name, pr.Index, gitea.ListPullReviewsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	p.Review = reviewStatus(reviews)
+
+	status, _, err := c.api.GetCombinedStatus(owner, name, pr.Head.Sha)
+	if err == nil && len(status.Statuses) > 0 {
+		p.CI = pipelineStatus(status.State)
+	}
+
+	return p, nil
+}
+
+// reviewStatus summarizes the latest review of every reviewer.
+func reviewStatus(reviews []*gitea.PullReview) vcs.ReviewStatus {
+	latest := map[string]gitea.ReviewStateType{}
+	for _, r := range reviews {
+		if r.Stale || r.Dismissed || r.Reviewer == nil {
+			continue
+		}
+		switch r.State {
+		case gitea.ReviewStateApproved, gitea.ReviewStateRequestChanges, gitea.ReviewStateRequestReview:
+			latest[r.Reviewer.UserName] = r.State
+		}
+	}
+
+	var status vcs.ReviewStatus
+	for _, state := range latest {
+		switch state {
+		case gitea.ReviewStateRequestChanges:
+			return vcs.ReviewChangesRequested
+		case gitea.ReviewStateRequestReview:
+			status = vcs.ReviewRequired
+		case gitea.ReviewStateApproved:
+			if status == "" {
+				status = vcs.ReviewApproved
+			}
+		}
+	}
+	return status
+}
+
+// isWIP returns whether a title carries one of the prefixes Gitea uses to mark
+// pull requests as work in progress.
+func isWIP(title string) bool {
+	t := strings.ToUpper(title)
+	return strings.HasPrefix(t, "WIP") || strings.HasPrefix(t, "[WIP]")
+}
+
+// issueFromAPI converts an issue of the Gitea API. Issues are numbered by
+// their index in the repository, their ID is unique across the instance.
+func issueFromAPI(v *gitea.Issue) vcs.Issue {
+	issue := vcs.Issue{
+		ID:        int(v.Index),
+		Body:      v.Body,
+		Title:     v.Title,
+		State:     string(v.State),
+		CreatedAt: v.Created,
+		URL:       v.HTMLURL,
+	}
+	if v.Poster != nil {
+		issue.Author = v.Poster.UserName
+	}
+	if v.Milestone != nil {
+		issue.Milestone = v.Milestone.Title
+	}
+	for _, l := range v.Labels {
+		issue.Labels = append(issue.Labels, vcs.Label{
+			Name:  l.Name,
+			Color: "#" + l.Color,
+		})
+	}
+	return issue
+}
+
+// pullRequestFromAPI converts a pull request of the Gitea API, numbered like
+// issues.
+func pullRequestFromAPI(v *gitea.PullRequest) vcs.PullRequest {
+	pr := vcs.PullRequest{
+		ID:        int(v.Index),
+		Title:     v.Title,
+		State:     pullRequestState(v),
+		CreatedAt: *v.Created,
+		URL:       v.HTMLURL,
+	}
+	if v.Poster != nil {
+		pr.Author = v.Poster.UserName
+	}
+	for _, l := range v.Labels {
+		pr.Labels = append(pr.Labels, vcs.Label{
+			Name:  l.Name,
+			Color: "#" + l.Color,
+		})
+	}
+	return pr
+}
+
+// pullRequestState returns the state of a pull request. Gitea considers merged
+// pull requests closed.
+func pullRequestState(pr *gitea.PullRequest) string {
+	if pr.HasMerged {
+		return "merged"
+	}
+	return string(pr.State)
+}
+
+// Issue returns the issue with the given number.
+func (c *Client) Issue(owner string, name string, number int) (*vcs.Issue, error) {
+	v, resp, err := c.api.GetIssue(owner, name, int64(number))
+	if err != nil {
+		return nil, notFound(err, resp, "issue", number)
+	}
+	// pull requests are issues, too
+	if v.PullRequest != nil {
+		return nil, fmt.Errorf("issue %d %w", number, vcs.ErrNotFound)
+	}
+
+	issue := issueFromAPI(v)
+	return &issue, nil
+}
+
+// IssueComments returns the comments of the issue with the given number.
+func (c *Client) IssueComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	var comments []vcs.Comment
+	for page := 1; ; page++ {
+		cs, _, err := c.api.ListIssueComments(owner, name, int64(number), gitea.ListIssueCommentOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(cs) == 0 {
+			break
+		}
+
+		for _, v := range cs {
+			comment := vcs.Comment{
+				Body:      v.Body,
+				CreatedAt: v.Created,
+				URL:       v.HTMLURL,
+			}
+			if v.Poster != nil {
+				comment.Author = v.Poster.UserName
+			}
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, nil
+}
+
+// PullRequestComments returns the comments of the pull request with the given
+// number. Gitea stores them like issue comments.
+func (c *Client) PullRequestComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	return c.IssueComments(owner, name, number)
+}
+
+// CommitsSince compares the latest release of a repository to its default
+// branch. It returns the latest max commits and the total amount of commits
+// since the release. This requires Gitea 1.20 or newer.
+func (c *Client) CommitsSince(repo vcs.Repo, max int) ([]vcs.Commit, int, error) {
+	var cmp struct {
+		TotalCommits int             `json:"total_commits"`
+		Commits      []*gitea.Commit `json:"commits"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", url.PathEscape(repo.Owner), url.PathEscape(repo.Name),
+		url.PathEscape(repo.LastRelease.TagName), url.PathEscape(repo.DefaultBranch))
+	if err := c.get(path, &cmp); err != nil {
+		return nil, 0, err
+	}
+
+	var cc []*gitea.Commit
+	for _, v := range cmp.Commits {
+		if v.CommitMeta != nil {
+			cc = append(cc, v)
+		}
+	}
+	sort.Slice(cc, func(i, j int) bool {
+		return cc[i].Created.After(cc[j].Created)
+	})
+
+	var commits []vcs.Commit
+	for _, v := range cc {
+		if max > 0 && len(commits) >= max {
+			break
+		}
+		commit := vcs.Commit{
+			ID:          v.SHA,
+			CommittedAt: v.Created,
+			URL:         v.HTMLURL,
+		}
+		if v.RepoCommit != nil {
+			commit.MessageHeadline = trimMessage(v.RepoCommit.Message)
+		}
+		if v.Author != nil {
+			commit.Author = v.Author.UserName
+		}
+		commits = append(commits, commit)
+	}
+
+	total := cmp.TotalCommits
+	if total < len(cmp.Commits) {
+		total = len(cmp.Commits)
+	}
+	return commits, total, nil
+}
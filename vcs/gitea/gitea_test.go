@@ -0,0 +1,117 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/muesli/gitty/vcs"
+)
+
+// testClient returns a client for a fake Gitea API.
+func testClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	api, err := gitea.NewClient(ts.URL, gitea.SetGiteaVersion("1.15.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{api: api, transport: vcs.NewRateLimitTransport(nil)}
+}
+
+// Issues and pull requests are numbered per repository by their index. Their
+// ID is unique across the whole instance.
+func TestIssueNumbers(t *testing.T) {
+	c := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "1")
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/repos/muesli/gitty/issues":
+			// pull requests are issues too, so they have to be filtered
+			if typ := r.URL.Query().Get("type"); typ != "issues" {
+				t.Errorf("Expected to list issues only, got type %q", typ)
+			}
+			fmt.Fprint(w, `[{"id": 9001, "number": 12, "title": "Issue", "created_at": "2021-01-01T00:00:00Z"}]`)
+		case "/api/v1/repos/muesli/gitty/pulls":
+			fmt.Fprint(w, `[{"id": 9002, "number": 13, "title": "PR", "created_at": "2021-01-01T00:00:00Z"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	issues, total, err := c.Issues("muesli", "gitty", vcs.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].ID != 12 || total != 1 {
+		t.Errorf("Expected issue 12, got %+v (total %d)", issues, total)
+	}
+
+	prs, total, err := c.PullRequests("muesli", "gitty", vcs.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].ID != 13 || total != 1 {
+		t.Errorf("Expected pull request 13, got %+v (total %d)", prs, total)
+	}
+}
+
+func TestIssuesOfMilestone(t *testing.T) {
+	c := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if m := r.URL.Query().Get("milestones"); m != "v1.0.0" {
+			t.Errorf("Expected to filter by milestone v1.0.0, got %q", m)
+		}
+		w.Header().Set("X-Total-Count", "1")
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 9001, "number": 12, "title": "Issue", "created_at": "2021-01-01T00:00:00Z"}]`)
+	})
+
+	issues, total, err := c.Issues("muesli", "gitty", vcs.ListOptions{Milestone: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || total != 1 {
+		t.Errorf("Expected one issue, got %+v (total %d)", issues, total)
+	}
+}
+
+func TestMilestonesPages(t *testing.T) {
+	c := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if limit := r.URL.Query().Get("limit"); limit != "50" {
+			t.Errorf("Expected pages of 50 milestones, got %q", limit)
+		}
+
+		// a full first page is followed by a partial one
+		n := 50
+		if r.URL.Query().Get("page") != "1" {
+			n = 3
+		}
+		fmt.Fprint(w, "[")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id": %d, "title": "v%d"}`, i+1, i+1)
+		}
+		fmt.Fprint(w, "]")
+	})
+
+	ms, err := c.Milestones("muesli", "gitty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 53 {
+		t.Errorf("Expected 53 milestones, got %d", len(ms))
+	}
+}
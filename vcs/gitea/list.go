@@ -0,0 +1,29 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/muesli/gitty/vcs"
+)
+
+// totalCount returns the total amount of items Gitea reported in the
+// X-Total-Count header of a list response.
+func totalCount(resp *gitea.Response) int {
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	return n
+}
+
+// notFound turns a 404 response into vcs.ErrNotFound. Other errors are
+// returned as they are.
+func notFound(err error, resp *gitea.Response, kind string, number int) error {
+	if resp != nil && resp.Response != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %d %w", kind, number, vcs.ErrNotFound)
+	}
+	return err
+}
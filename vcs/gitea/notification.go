@@ -0,0 +1,84 @@
+package gitea
+
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/muesli/gitty/vcs"
+)
+
+// subjectType returns the kind of a notification's subject.
+func subjectType(t gitea.NotifySubjectType) string {
+	if t == gitea.NotifySubjectPull {
+		return "pull request"
+	}
+	return strings.ToLower(string(t))
+}
+
+// subjectURL returns the web URL of a notification's subject, which Gitea only
+// reports as an API URL.
+func subjectURL(n *gitea.NotificationThread) string {
+	if n.Subject.URL == "" && n.Repository != nil {
+		return n.Repository.HTMLURL
+	}
+	return strings.Replace(n.Subject.URL, "/api/v1/repos/", "/", 1)
+}
+
+// Notifications returns the unread notifications of the authenticated user.
+func (c *Client) Notifications() ([]vcs.Notification, error) {
+	var notifications []vcs.Notification
+	for page := 1; ; page++ {
+		nn, _, err := c.api.ListNotifications(gitea.ListNotificationOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+			Status: []gitea.NotifyStatus{gitea.NotifyStatusUnread},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(nn) == 0 {
+			break
+		}
+
+		for _, v := range nn {
+			if v.Subject == nil {
+				continue
+			}
+			n := vcs.Notification{
+				ID:        strconv.FormatInt(v.ID, 10),
+				Title:     v.Subject.Title,
+				Type:      subjectType(v.Subject.Type),
+				UpdatedAt: v.UpdatedAt,
+				URL:       subjectURL(v),
+			}
+			if v.Repository != nil {
+				n.Repo = v.Repository.FullName
+			}
+			notifications = append(notifications, n)
+		}
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationRead marks a notification as read.
+func (c *Client) MarkNotificationRead(id string) error {
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = c.api.ReadNotification(i, gitea.NotifyStatusRead)
+	return err
+}
+
+// MarkAllNotificationsRead marks all notifications as read.
+func (c *Client) MarkAllNotificationsRead() error {
+	_, err := c.api.ReadNotifications(gitea.MarkNotificationOptions{
+		Status:   []gitea.NotifyStatus{gitea.NotifyStatusUnread},
+		ToStatus: gitea.NotifyStatusRead,
+	})
+	return err
+}
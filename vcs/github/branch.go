@@ -7,7 +7,7 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
-var branchesQuery struct {
+type branchesQuery struct {
 	Repository struct {
 		Refs struct {
 			Nodes []struct {
@@ -16,26 +16,42 @@ var branchesQuery struct {
 					Commit qlCommit `graphql:"... on Commit"`
 				}
 			}
-		} `graphql:"refs(first: 100, refPrefix: \"refs/heads/\")"`
+		} `graphql:"refs(first: $first, refPrefix: \"refs/heads/\", orderBy: $orderBy)"`
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
-// Branches returns a list of branches for the given repository.
-func (c *Client) Branches(owner string, name string) ([]vcs.Branch, error) {
+// Branches returns a list of up to 100 branches for the given repository.
+// Sorted by SortUpdated, the branches with the latest commits come first,
+// otherwise they're sorted by name.
+func (c *Client) Branches(owner string, name string, opts vcs.ListOptions) ([]vcs.Branch, error) {
+	var query branchesQuery
+
+	order := githubv4.RefOrder{
+		Field:     githubv4.RefOrderFieldAlphabetical,
+		Direction: githubv4.OrderDirectionAsc,
+	}
+	if opts.Sort == vcs.SortUpdated {
+		order.Field = githubv4.RefOrderFieldTagCommitDate
+		order.Direction = githubv4.OrderDirectionDesc
+	}
+
 	variables := map[string]interface{}{
-		"owner": githubv4.String(owner),
-		"name":  githubv4.String(name),
+		"owner":   githubv4.String(owner),
+		"name":    githubv4.String(name),
+		"first":   githubv4.Int(opts.PerPage(100)),
+		"orderBy": order,
 	}
 
-	if err := c.queryWithRetry(context.Background(), &branchesQuery, variables); err != nil {
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
 		return nil, err
 	}
 
 	var branches []vcs.Branch //nolint
-	for _, node := range branchesQuery.Repository.Refs.Nodes {
+	for _, node := range query.Repository.Refs.Nodes {
 		branches = append(branches, vcs.Branch{
 			Name:       string(node.Name),
 			LastCommit: commitFromQL(node.Target.Commit),
+			URL:        c.BranchURL(owner, name, string(node.Name)),
 		})
 	}
 
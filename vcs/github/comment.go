@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type singleIssueQuery struct {
+	Repository struct {
+		IssueOrPullRequest *struct {
+			Issue qlIssue `graphql:"... on Issue"`
+		} `graphql:"issueOrPullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type qlComments struct {
+	PageInfo struct {
+		EndCursor   githubv4.String
+		HasNextPage githubv4.Boolean
+	}
+	Nodes []struct {
+		Author struct {
+			Login githubv4.String
+		}
+		Body      githubv4.String
+		CreatedAt githubv4.DateTime
+		URL       githubv4.String
+	}
+}
+
+type issueCommentsQuery struct {
+	Repository struct {
+		Issue struct {
+			Comments qlComments `graphql:"comments(first: 100, after: $after)"`
+		} `graphql:"issue(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type pullRequestCommentsQuery struct {
+	Repository struct {
+		PullRequest struct {
+			Comments qlComments `graphql:"comments(first: 100, after: $after)"`
+		} `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// Issue returns the issue with the given number.
+func (c *Client) Issue(owner string, name string, number int) (*vcs.Issue, error) {
+	var query singleIssueQuery
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+	}
+
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return nil, notFound(err, "issue", number)
+	}
+	// pull requests are issues, too
+	if query.Repository.IssueOrPullRequest == nil || query.Repository.IssueOrPullRequest.Issue.Number == 0 {
+		return nil, fmt.Errorf("issue %d %w", number, vcs.ErrNotFound)
+	}
+
+	i := issueFromQL(query.Repository.IssueOrPullRequest.Issue)
+	return &i, nil
+}
+
+// IssueComments returns the comments of the issue with the given number.
+func (c *Client) IssueComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	var query issueCommentsQuery
+	return c.comments(&query, &query.Repository.Issue.Comments, owner, name, number)
+}
+
+// PullRequestComments returns the comments of the pull request with the given
+// number.
+func (c *Client) PullRequestComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	var query pullRequestCommentsQuery
+	return c.comments(&query, &query.Repository.PullRequest.Comments, owner, name, number)
+}
+
+// comments pages through the comments of a query. page points at the comments
+// within query.
+func (c *Client) comments(query interface{}, page *qlComments, owner string, name string, number int) ([]vcs.Comment, error) {
+	var comments []vcs.Comment
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+		"after":  (*githubv4.String)(nil),
+	}
+
+	for {
+		if err := c.queryWithRetry(context.Background(), query, variables); err != nil {
+			return comments, err
+		}
+
+		for _, v := range page.Nodes {
+			comments = append(comments, vcs.Comment{
+				Author:    string(v.Author.Login),
+				Body:      string(v.Body),
+				CreatedAt: v.CreatedAt.Time,
+				URL:       string(v.URL),
+			})
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = githubv4.NewString(page.PageInfo.EndCursor)
+	}
+
+	return comments, nil
+}
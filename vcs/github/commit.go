@@ -2,26 +2,31 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/muesli/gitty/vcs"
 	"github.com/shurcooL/githubv4"
 )
 
-var historyQuery struct {
+type historyQuery struct {
 	Repository struct {
 		Object struct {
 			Commit struct {
 				Oid     githubv4.String
 				History struct {
 					TotalCount githubv4.Int
-					Edges      []struct {
+					PageInfo   struct {
+						EndCursor   githubv4.String
+						HasNextPage githubv4.Boolean
+					}
+					Edges []struct {
 						Cursor githubv4.String
 						Node   struct {
 							qlCommit
 						}
 					}
-				} `graphql:"history(first: 100, since: $since)"`
+				} `graphql:"history(first: 100, since: $since, after: $after)"`
 			} `graphql:"... on Commit"`
 		} `graphql:"object(expression: \"HEAD\")"`
 	} `graphql:"repository(owner: $owner, name: $name)"`
@@ -31,6 +36,7 @@ type qlCommit struct {
 	OID             githubv4.GitObjectID
 	MessageHeadline githubv4.String
 	CommittedDate   githubv4.GitTimestamp
+	URL             githubv4.String
 	Author          struct {
 		User struct {
 			Login githubv4.String
@@ -38,27 +44,36 @@ type qlCommit struct {
 	}
 }
 
-// History returns a list of commits for the given repository.
+// History returns a list of commits for the given repository, up to max
+// commits if max is greater than zero.
 func (c *Client) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit, error) {
+	var query historyQuery
 	var commits []vcs.Commit //nolint
 
 	variables := map[string]interface{}{
 		"owner": githubv4.String(repo.Owner),
 		"name":  githubv4.String(repo.Name),
 		"since": githubv4.GitTimestamp{Time: since},
+		"after": (*githubv4.String)(nil),
 	}
 
-	// if err := client.Query(context.Background(), &historyQuery, variables); err != nil {
-	if err := c.queryWithRetry(context.Background(), &historyQuery, variables); err != nil {
-		return commits, err
-	}
+	for {
+		if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+			return commits, err
+		}
 
-	for _, v := range historyQuery.Repository.Object.Commit.History.Edges {
-		if v.Node.qlCommit.OID == "" {
-			// fmt.Println("Commit ID broken:", v.Node.QLCommit.OID)
-			continue
+		history := query.Repository.Object.Commit.History
+		for _, v := range history.Edges {
+			if v.Node.qlCommit.OID == "" {
+				continue
+			}
+			commits = append(commits, commitFromQL(v.Node.qlCommit))
 		}
-		commits = append(commits, commitFromQL(v.Node.qlCommit))
+
+		if !history.PageInfo.HasNextPage || (max > 0 && len(commits) >= max) {
+			break
+		}
+		variables["after"] = githubv4.NewString(history.PageInfo.EndCursor)
 	}
 
 	return commits, nil
@@ -70,5 +85,52 @@ func commitFromQL(commit qlCommit) vcs.Commit {
 		MessageHeadline: string(commit.MessageHeadline),
 		CommittedAt:     commit.CommittedDate.Time,
 		Author:          string(commit.Author.User.Login),
+		URL:             string(commit.URL),
+	}
+}
+
+type compareQuery struct {
+	Repository struct {
+		Ref *struct {
+			Compare struct {
+				AheadBy githubv4.Int
+				Commits struct {
+					Nodes []qlCommit
+				} `graphql:"commits(last: $max)"`
+			} `graphql:"compare(headRef: $head)"`
+		} `graphql:"ref(qualifiedName: $tag)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// CommitsSince compares the latest release of a repository to its default
+// branch. It returns the latest max commits, up to 100, and the total amount
+// of commits since the release.
+func (c *Client) CommitsSince(repo vcs.Repo, max int) ([]vcs.Commit, int, error) {
+	if max <= 0 || max > 100 {
+		max = 100
+	}
+
+	var query compareQuery
+	variables := map[string]interface{}{
+		"owner": githubv4.String(repo.Owner),
+		"name":  githubv4.String(repo.Name),
+		"tag":   githubv4.String("refs/tags/" + repo.LastRelease.TagName),
+		"head":  githubv4.String(repo.DefaultBranch),
+		"max":   githubv4.Int(max),
 	}
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return nil, 0, err
+	}
+	if query.Repository.Ref == nil {
+		return nil, 0, fmt.Errorf("tag %s not found", repo.LastRelease.TagName)
+	}
+
+	// compared commits are sorted oldest first
+	nodes := query.Repository.Ref.Compare.Commits.Nodes
+	commits := make([]vcs.Commit, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		commits = append(commits, commitFromQL(nodes[i]))
+	}
+
+	return commits, int(query.Repository.Ref.Compare.AheadBy), nil
 }
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type repositoryIDsQuery struct {
+	Repository struct {
+		ID     githubv4.ID
+		Labels struct {
+			Nodes []struct {
+				ID   githubv4.ID
+				Name githubv4.String
+			}
+		} `graphql:"labels(first: 100)"`
+		Milestones struct {
+			Nodes []struct {
+				ID    githubv4.ID
+				Title githubv4.String
+			}
+		} `graphql:"milestones(first: 100, states: OPEN)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type userIDQuery struct {
+	User struct {
+		ID githubv4.ID
+	} `graphql:"user(login: $login)"`
+}
+
+// issueFieldIDs are the node IDs GitHub's mutations expect instead of names.
+type issueFieldIDs struct {
+	repository githubv4.ID
+	labels     *[]githubv4.ID
+	assignees  *[]githubv4.ID
+	milestone  *githubv4.ID
+}
+
+// issueFields looks up the IDs of a repository and the given labels, assignees
+// and milestone.
+func (c *Client) issueFields(owner, name string, labels, assignees []string, milestone string) (issueFieldIDs, error) {
+	var ids issueFieldIDs
+
+	var query repositoryIDsQuery
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+	}
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return ids, err
+	}
+	ids.repository = query.Repository.ID
+
+	if len(labels) > 0 {
+		var ll []githubv4.ID
+		for _, l := range labels {
+			var found bool
+			for _, v := range query.Repository.Labels.Nodes {
+				if strings.EqualFold(string(v.Name), l) {
+					ll = append(ll, v.ID)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return ids, fmt.Errorf("unknown label: %s", l)
+			}
+		}
+		ids.labels = &ll
+	}
+
+	if len(assignees) > 0 {
+		var aa []githubv4.ID
+		for _, a := range assignees {
+			var user userIDQuery
+			if err := c.queryWithRetry(context.Background(), &user, map[string]interface{}{
+				"login": githubv4.String(a),
+			}); err != nil {
+				return ids, fmt.Errorf("unknown user %s: %v", a, err)
+			}
+			aa = append(aa, user.User.ID)
+		}
+		ids.assignees = &aa
+	}
+
+	if milestone != "" {
+		for _, v := range query.Repository.Milestones.Nodes {
+			if string(v.Title) == milestone {
+				id := v.ID
+				ids.milestone = &id
+				break
+			}
+		}
+		if ids.milestone == nil {
+			return ids, fmt.Errorf("unknown milestone: %s", milestone)
+		}
+	}
+
+	return ids, nil
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(owner string, name string, issue vcs.NewIssue) (*vcs.Issue, error) {
+	ids, err := c.issueFields(owner, name, issue.Labels, issue.Assignees, issue.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	var m struct {
+		CreateIssue struct {
+			Issue qlIssue
+		} `graphql:"createIssue(input: $input)"`
+	}
+	input := githubv4.CreateIssueInput{
+		RepositoryID: ids.repository,
+		Title:        githubv4.String(issue.Title),
+		Body:         githubv4.NewString(githubv4.String(issue.Body)),
+		LabelIDs:     ids.labels,
+		AssigneeIDs:  ids.assignees,
+		MilestoneID:  ids.milestone,
+	}
+	if err := c.api.Mutate(context.Background(), &m, input, nil); err != nil {
+		return nil, err
+	}
+
+	i := issueFromQL(m.CreateIssue.Issue)
+	return &i, nil
+}
+
+// CreatePullRequest creates a new pull request.
+func (c *Client) CreatePullRequest(owner string, name string, pr vcs.NewPullRequest) (*vcs.PullRequest, error) {
+	ids, err := c.issueFields(owner, name, pr.Labels, pr.Assignees, pr.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	var m struct {
+		CreatePullRequest struct {
+			PullRequest struct {
+				ID githubv4.ID
+				qlPullRequest
+			}
+		} `graphql:"createPullRequest(input: $input)"`
+	}
+	input := githubv4.CreatePullRequestInput{
+		RepositoryID: ids.repository,
+		BaseRefName:  githubv4.String(pr.Base),
+		HeadRefName:  githubv4.String(pr.Head),
+		Title:        githubv4.String(pr.Title),
+		Body:         githubv4.NewString(githubv4.String(pr.Body)),
+	}
+	if err := c.api.Mutate(context.Background(), &m, input, nil); err != nil {
+		return nil, err
+	}
+	p := pullRequestFromQL(m.CreatePullRequest.PullRequest.qlPullRequest)
+	if ids.labels == nil && ids.assignees == nil && ids.milestone == nil {
+		return &p, nil
+	}
+
+	// labels, assignees and milestones can only be set after the fact
+	var u struct {
+		UpdatePullRequest struct {
+			PullRequest qlPullRequest
+		} `graphql:"updatePullRequest(input: $input)"`
+	}
+	update := githubv4.UpdatePullRequestInput{
+		PullRequestID: m.CreatePullRequest.PullRequest.ID,
+		LabelIDs:      ids.labels,
+		AssigneeIDs:   ids.assignees,
+		MilestoneID:   ids.milestone,
+	}
+	if err := c.api.Mutate(context.Background(), &u, update, nil); err != nil {
+		return &p, fmt.Errorf("created pull request #%d, but can't update it: %v", p.ID, err)
+	}
+
+	p = pullRequestFromQL(u.UpdatePullRequest.PullRequest)
+	return &p, nil
+}
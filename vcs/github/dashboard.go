@@ -0,0 +1,77 @@
+package github
+
+import (
+	"context"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type qlSearchRepository struct {
+	Repository struct {
+		NameWithOwner githubv4.String
+	}
+}
+
+type dashboardQuery struct {
+	Assigned struct {
+		Nodes []struct {
+			Issue struct {
+				qlIssue
+				qlSearchRepository
+			} `graphql:"... on Issue"`
+		}
+	} `graphql:"assigned: search(query: $assigned, type: ISSUE, first: 50)"`
+	ReviewRequests struct {
+		Nodes []struct {
+			PullRequest struct {
+				qlPullRequestStatus
+				qlSearchRepository
+			} `graphql:"... on PullRequest"`
+		}
+	} `graphql:"reviewRequests: search(query: $reviewRequests, type: ISSUE, first: 50)"`
+	Authored struct {
+		Nodes []struct {
+			PullRequest struct {
+				qlPullRequestStatus
+				qlSearchRepository
+			} `graphql:"... on PullRequest"`
+		}
+	} `graphql:"authored: search(query: $authored, type: ISSUE, first: 50)"`
+}
+
+// Dashboard returns the open issues assigned to a user, the pull requests
+// awaiting their review, and their own open pull requests.
+func (c *Client) Dashboard(username string) (vcs.Dashboard, error) {
+	var query dashboardQuery
+	variables := map[string]interface{}{
+		"assigned":       githubv4.String("is:open is:issue archived:false sort:updated-desc assignee:" + username),
+		"reviewRequests": githubv4.String("is:open is:pr archived:false sort:updated-desc review-requested:" + username),
+		"authored":       githubv4.String("is:open is:pr archived:false sort:updated-desc author:" + username),
+	}
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return vcs.Dashboard{}, err
+	}
+
+	var d vcs.Dashboard
+	for _, v := range query.Assigned.Nodes {
+		d.Assigned = append(d.Assigned, vcs.AssignedIssue{
+			Issue: issueFromQL(v.Issue.qlIssue),
+			Repo:  string(v.Issue.Repository.NameWithOwner),
+		})
+	}
+	for _, v := range query.ReviewRequests.Nodes {
+		d.ReviewRequests = append(d.ReviewRequests, vcs.UserPullRequest{
+			PullRequestStatus: *pullRequestStatusFromQL(v.PullRequest.qlPullRequestStatus),
+			Repo:              string(v.PullRequest.Repository.NameWithOwner),
+		})
+	}
+	for _, v := range query.Authored.Nodes {
+		d.Authored = append(d.Authored, vcs.UserPullRequest{
+			PullRequestStatus: *pullRequestStatusFromQL(v.PullRequest.qlPullRequestStatus),
+			Repo:              string(v.PullRequest.Repository.NameWithOwner),
+		})
+	}
+
+	return d, nil
+}
@@ -2,51 +2,97 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/muesli/gitty/vcs"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
 
 // Client is a GitHub client.
 type Client struct {
-	api *githubv4.Client
+	api       *githubv4.Client
+	http      *http.Client
+	transport *vcs.RateLimitTransport
+	host      string
+	restURL   string
 }
 
-// NewClient creates a new GitHub client.
-func NewClient(token string) (*Client, error) {
+// NewClient creates a new client for github.com or a GitHub Enterprise Server
+// running on the given host. Unless preverified, the host gets checked to be
+// a GitHub Enterprise Server.
+func NewClient(host, token string, preverified bool) (*Client, error) {
 	var httpClient *http.Client
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 
-	httpClient = oauth2.NewClient(context.Background(), ts)
-	client := githubv4.NewClient(httpClient)
+	transport := vcs.NewRateLimitTransport(nil)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+		Transport: &graphQLErrorTransport{base: transport},
+	})
+	httpClient = oauth2.NewClient(ctx, ts)
 
 	c := &Client{
-		api: client,
+		http:      httpClient,
+		transport: transport,
+		host:      "github.com",
+		restURL:   "https://api.github.com",
+	}
+	if strings.EqualFold(host, "github.com") {
+		c.api = githubv4.NewClient(httpClient)
+		return c, nil
+	}
+
+	// GitHub Enterprise Server serves its APIs from the same host
+	c.host = host
+	c.restURL = "https://" + host + "/api/v3"
+	c.api = githubv4.NewEnterpriseClient("https://"+host+"/api/graphql", httpClient)
+
+	if !preverified {
+		var meta struct {
+			InstalledVersion string `json:"installed_version"`
+		}
+		if err := c.rest(http.MethodGet, "/meta", &meta); err != nil {
+			return nil, err
+		}
+		if meta.InstalledVersion == "" {
+			return nil, fmt.Errorf("%s is not a GitHub Enterprise Server", host)
+		}
 	}
 
 	return c, nil
 }
 
+// queryWithRetry runs a GraphQL query. The client's transport retries queries
+// rate limited with an error status, but GitHub also reports an exhausted
+// quota as a RATE_LIMITED error in a successful response. Such queries get
+// retried here.
 func (c *Client) queryWithRetry(ctx context.Context, q interface{}, variables map[string]interface{}) error {
-	if err := c.api.Query(ctx, q, variables); err != nil {
-		if strings.Contains(err.Error(), "abuse-rate-limits") {
-			time.Sleep(time.Minute)
-			return c.queryWithRetry(ctx, q, variables)
+	for attempt := 0; ; attempt++ {
+		err := c.api.Query(ctx, q, variables)
+		if !errors.Is(err, errRateLimited) {
+			return err
+		}
+		if err := c.transport.RetryAfterRateLimit(ctx, attempt); err != nil {
+			return err
 		}
-
-		return err
 	}
+}
 
-	return nil
+// notFound turns GraphQL errors about a missing issue or pull request into
+// vcs.ErrNotFound. Other errors are returned as they are.
+func notFound(err error, kind string, number int) error {
+	if strings.Contains(err.Error(), "Could not resolve to") {
+		return fmt.Errorf("%s %d %w", kind, number, vcs.ErrNotFound)
+	}
+	return err
 }
 
 // IssueURL returns the URL to the issue with the given number.
 func (c *Client) IssueURL(owner string, name string, number int) string {
-	return fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, name, number)
+	return fmt.Sprintf("%s/issues/%d", c.webURL(owner, name), number)
 }
@@ -2,22 +2,33 @@ package github
 
 import (
 	"context"
+	"strings"
 
 	"github.com/muesli/gitty/vcs"
 	"github.com/shurcooL/githubv4"
 )
 
-var issuesQuery struct {
+type qlIssueConnection struct {
+	TotalCount githubv4.Int
+	Edges      []struct {
+		Cursor githubv4.String
+		Node   struct {
+			qlIssue
+		}
+	}
+}
+
+type issuesQuery struct {
 	Repository struct {
-		Issues struct {
-			TotalCount githubv4.Int
-			Edges      []struct {
-				Cursor githubv4.String
-				Node   struct {
-					qlIssue
-				}
-			}
-		} `graphql:"issues(first: 100, after: $after, states: OPEN, orderBy: {field: CREATED_AT, direction: DESC})"`
+		Issues qlIssueConnection `graphql:"issues(first: $first, after: $after, states: OPEN, orderBy: $orderBy)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type milestoneIssuesQuery struct {
+	Repository struct {
+		Milestone *struct {
+			Issues qlIssueConnection `graphql:"issues(first: $first, after: $after, states: OPEN, orderBy: $orderBy)"`
+		} `graphql:"milestone(number: $number)"`
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
@@ -25,8 +36,16 @@ type qlIssue struct {
 	Number    githubv4.Int
 	Body      githubv4.String
 	Title     githubv4.String
+	State     githubv4.String
 	CreatedAt githubv4.DateTime
-	Labels    struct {
+	URL       githubv4.String
+	Author    struct {
+		Login githubv4.String
+	}
+	Milestone struct {
+		Title githubv4.String
+	}
+	Labels struct {
 		Edges []struct {
 			Cursor githubv4.String
 			Node   struct {
@@ -37,32 +56,75 @@ type qlIssue struct {
 	} `graphql:"labels(first: 100, orderBy: {field: NAME, direction: ASC})"`
 }
 
-// Issues returns a list of issues for the given repository.
-func (c *Client) Issues(owner string, name string) ([]vcs.Issue, error) {
+// Issues returns a list of issues for the given repository and their total
+// amount.
+func (c *Client) Issues(owner string, name string, opts vcs.ListOptions) ([]vcs.Issue, int, error) {
 	var issues []vcs.Issue
 
 	variables := map[string]interface{}{
-		"owner": githubv4.String(owner),
-		"name":  githubv4.String(name),
-		"after": (*githubv4.String)(nil),
+		"owner":   githubv4.String(owner),
+		"name":    githubv4.String(name),
+		"first":   githubv4.Int(opts.PerPage(100)),
+		"orderBy": issueOrder(opts.Sort),
+		"after":   (*githubv4.String)(nil),
+	}
+
+	// issues can only be filtered by the number of their milestone
+	if opts.Milestone != "" {
+		number, err := c.milestoneNumber(owner, name, opts.Milestone)
+		if err != nil || number == 0 {
+			return nil, 0, err
+		}
+		variables["number"] = githubv4.Int(number)
 	}
 
 	for {
-		if err := c.queryWithRetry(context.Background(), &issuesQuery, variables); err != nil {
-			return issues, err
+		conn, err := c.issuesPage(variables)
+		if err != nil {
+			return issues, 0, err
 		}
-		if len(issuesQuery.Repository.Issues.Edges) == 0 {
-			break
+		if len(conn.Edges) == 0 {
+			return issues, int(conn.TotalCount), nil
 		}
 
-		for _, v := range issuesQuery.Repository.Issues.Edges {
+		for _, v := range conn.Edges {
 			issues = append(issues, issueFromQL(v.Node.qlIssue))
 
 			variables["after"] = githubv4.NewString(v.Cursor)
 		}
+		if opts.Done(len(issues)) {
+			return issues[:opts.Limit], int(conn.TotalCount), nil
+		}
+	}
+}
+
+// issuesPage queries a page of open issues, of a milestone if variables
+// contain its number.
+func (c *Client) issuesPage(variables map[string]interface{}) (qlIssueConnection, error) {
+	if _, ok := variables["number"]; !ok {
+		var query issuesQuery
+		err := c.queryWithRetry(context.Background(), &query, variables)
+		return query.Repository.Issues, err
 	}
 
-	return issues, nil
+	var query milestoneIssuesQuery
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return qlIssueConnection{}, err
+	}
+	if query.Repository.Milestone == nil {
+		return qlIssueConnection{}, nil
+	}
+	return query.Repository.Milestone.Issues, nil
+}
+
+// issueOrder returns the GitHub order of issues and pull requests for the
+// given sort order.
+func issueOrder(sort vcs.SortOrder) githubv4.IssueOrder {
+	field := githubv4.IssueOrderFieldCreatedAt
+	if sort == vcs.SortUpdated {
+		field = githubv4.IssueOrderFieldUpdatedAt
+	}
+	return githubv4.IssueOrder{Field: field, Direction: githubv4.OrderDirectionDesc}
 }
 
 func issueFromQL(issue qlIssue) vcs.Issue {
@@ -70,7 +132,11 @@ func issueFromQL(issue qlIssue) vcs.Issue {
 		ID:        int(issue.Number),
 		Body:      string(issue.Body),
 		Title:     string(issue.Title),
+		Author:    string(issue.Author.Login),
+		State:     strings.ToLower(string(issue.State)),
 		CreatedAt: issue.CreatedAt.Time,
+		URL:       string(issue.URL),
+		Milestone: string(issue.Milestone.Title),
 	}
 
 	for _, v := range issue.Labels.Edges {
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type milestonesQuery struct {
+	Repository struct {
+		Milestones struct {
+			Nodes []qlMilestone
+		} `graphql:"milestones(first: 100, states: OPEN, orderBy: {field: DUE_DATE, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type qlMilestone struct {
+	Number     githubv4.Int
+	Title      githubv4.String
+	DueOn      githubv4.DateTime
+	URL        githubv4.String
+	OpenIssues struct {
+		TotalCount githubv4.Int
+	} `graphql:"openIssues: issues(states: OPEN)"`
+	ClosedIssues struct {
+		TotalCount githubv4.Int
+	} `graphql:"closedIssues: issues(states: CLOSED)"`
+}
+
+type milestoneNumberQuery struct {
+	Repository struct {
+		Milestones struct {
+			Nodes []struct {
+				Number githubv4.Int
+				Title  githubv4.String
+			}
+		} `graphql:"milestones(first: 100, query: $title)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// milestoneNumber returns the number of the milestone with the given title,
+// or 0 if there is none.
+func (c *Client) milestoneNumber(owner string, name string, title string) (int, error) {
+	var query milestoneNumberQuery
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+		"title": githubv4.String(title),
+	}
+
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return 0, err
+	}
+
+	// the query also matches milestones containing the title
+	for _, v := range query.Repository.Milestones.Nodes {
+		if strings.EqualFold(string(v.Title), title) {
+			return int(v.Number), nil
+		}
+	}
+	return 0, nil
+}
+
+// Milestones returns the open milestones of the given repository.
+func (c *Client) Milestones(owner string, name string) ([]vcs.Milestone, error) {
+	var query milestonesQuery
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+	}
+
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return nil, err
+	}
+
+	var milestones []vcs.Milestone //nolint
+	for _, v := range query.Repository.Milestones.Nodes {
+		milestones = append(milestones, milestoneFromQL(v))
+	}
+
+	// GitHub lists milestones without a due date first
+	vcs.SortMilestones(milestones)
+	return milestones, nil
+}
+
+func milestoneFromQL(m qlMilestone) vcs.Milestone {
+	return vcs.Milestone{
+		ID:           int(m.Number),
+		Title:        string(m.Title),
+		DueOn:        m.DueOn.Time,
+		OpenIssues:   int(m.OpenIssues.TotalCount),
+		ClosedIssues: int(m.ClosedIssues.TotalCount),
+		URL:          string(m.URL),
+	}
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+type restNotification struct {
+	ID        string    `json:"id"`
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Subject   struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Type  string `json:"type"`
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// rest sends a request to the REST API, which is the only API that supports
+// notifications, and decodes its response into v, unless v is nil.
+func (c *Client) rest(method string, path string, v interface{}) error {
+	req, err := http.NewRequest(method, c.restURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// subjectURL returns the web URL of a notification's subject, which GitHub
+// only reports as an API URL.
+func (c *Client) subjectURL(n restNotification) string {
+	switch {
+	case n.Subject.URL == "":
+		return n.Repository.HTMLURL
+	case n.Subject.Type == "Release":
+		// release URLs contain their ID instead of the tag
+		return n.Repository.HTMLURL + "/releases"
+	}
+
+	u := strings.Replace(n.Subject.URL, c.restURL+"/repos/", "https://"+c.host+"/", 1)
+	u = strings.Replace(u, "/pulls/", "/pull/", 1)
+	return strings.Replace(u, "/commits/", "/commit/", 1)
+}
+
+// Notifications returns the unread notifications of the authenticated user.
+func (c *Client) Notifications() ([]vcs.Notification, error) {
+	var notifications []vcs.Notification
+	for page := 1; ; page++ {
+		var nn []restNotification
+		if err := c.rest(http.MethodGet, fmt.Sprintf("/notifications?per_page=50&page=%d", page), &nn); err != nil {
+			return nil, err
+		}
+		if len(nn) == 0 {
+			break
+		}
+
+		for _, v := range nn {
+			notifications = append(notifications, vcs.Notification{
+				ID:        v.ID,
+				Repo:      v.Repository.FullName,
+				Title:     v.Subject.Title,
+				Type:      vcs.SubjectType(v.Subject.Type),
+				Reason:    strings.ReplaceAll(v.Reason, "_", " "),
+				UpdatedAt: v.UpdatedAt,
+				URL:       c.subjectURL(v),
+			})
+		}
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationRead marks a notification as read.
+func (c *Client) MarkNotificationRead(id string) error {
+	return c.rest(http.MethodPatch, "/notifications/threads/"+id, nil)
+}
+
+// MarkAllNotificationsRead marks all notifications as read.
+func (c *Client) MarkAllNotificationsRead() error {
+	return c.rest(http.MethodPut, "/notifications", nil)
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type qlRef struct {
+	Target struct {
+		Commit struct {
+			CheckSuites struct {
+				Nodes []qlCheckSuite
+			} `graphql:"checkSuites(last: 20)"`
+		} `graphql:"... on Commit"`
+	}
+}
+
+// maxPipelineRefs limits how many branches get queried at once.
+const maxPipelineRefs = 25
+
+// pipelinesQuery returns a query for the refs of n branches, aliased as ref0
+// to refN.
+func pipelinesQuery(n int) interface{} {
+	refs := make([]reflect.StructField, n)
+	for i := range refs {
+		refs[i] = reflect.StructField{
+			Name: fmt.Sprintf("Ref%d", i),
+			Type: reflect.TypeOf(qlRef{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"ref%d: ref(qualifiedName: $ref%d)"`, i, i)),
+		}
+	}
+
+	query := reflect.StructOf([]reflect.StructField{{
+		Name: "Repository",
+		Type: reflect.StructOf(refs),
+		Tag:  `graphql:"repository(owner: $owner, name: $name)"`,
+	}})
+	return reflect.New(query).Interface()
+}
+
+type qlCheckSuite struct {
+	Status     githubv4.CheckStatusState
+	Conclusion githubv4.CheckConclusionState
+	CreatedAt  githubv4.DateTime
+	UpdatedAt  githubv4.DateTime
+	URL        githubv4.String
+	App        struct {
+		Name githubv4.String
+	}
+	WorkflowRun struct {
+		URL      githubv4.String
+		Workflow struct {
+			Name githubv4.String
+		}
+	}
+	AllRuns struct {
+		TotalCount githubv4.Int
+	} `graphql:"allRuns: checkRuns"`
+	FailedRuns struct {
+		Nodes []struct {
+			Name githubv4.String
+		}
+	} `graphql:"failedRuns: checkRuns(first: 1, filterBy: {conclusions: [FAILURE, TIMED_OUT, STARTUP_FAILURE]})"`
+}
+
+// Pipelines returns the latest CI status of the given branches. The branches
+// get queried in batches, with a single request for most repositories.
+func (c *Client) Pipelines(owner string, name string, branches []string) ([]vcs.Pipeline, error) {
+	var pipelines []vcs.Pipeline //nolint
+
+	for len(branches) > 0 {
+		batch := branches
+		if len(batch) > maxPipelineRefs {
+			batch = batch[:maxPipelineRefs]
+		}
+		branches = branches[len(batch):]
+
+		query := pipelinesQuery(len(batch))
+		variables := map[string]interface{}{
+			"owner": githubv4.String(owner),
+			"name":  githubv4.String(name),
+		}
+		for i, branch := range batch {
+			variables[fmt.Sprintf("ref%d", i)] = githubv4.String("refs/heads/" + branch)
+		}
+
+		if err := c.queryWithRetry(context.Background(), query, variables); err != nil {
+			return pipelines, err
+		}
+
+		refs := reflect.ValueOf(query).Elem().Field(0)
+		for i, branch := range batch {
+			ref := refs.Field(i).Interface().(qlRef)
+
+			var runs []vcs.Pipeline
+			for _, v := range ref.Target.Commit.CheckSuites.Nodes {
+				// every installed app gets a check suite, even if it never runs
+				if v.AllRuns.TotalCount == 0 && v.WorkflowRun.URL == "" {
+					continue
+				}
+				runs = append(runs, pipelineFromQL(v))
+			}
+			if len(runs) == 0 {
+				continue
+			}
+
+			pipelines = append(pipelines, vcs.SummarizePipelines(branch, runs))
+		}
+	}
+
+	return pipelines, nil
+}
+
+func pipelineFromQL(suite qlCheckSuite) vcs.Pipeline {
+	p := vcs.Pipeline{
+		Name:      string(suite.App.Name),
+		StartedAt: suite.CreatedAt.Time,
+		URL:       string(suite.URL),
+	}
+	if suite.WorkflowRun.URL != "" {
+		p.Name = string(suite.WorkflowRun.Workflow.Name)
+		p.URL = string(suite.WorkflowRun.URL)
+	}
+	if len(suite.FailedRuns.Nodes) > 0 {
+		p.FailedJob = string(suite.FailedRuns.Nodes[0].Name)
+	}
+
+	switch suite.Status {
+	case githubv4.CheckStatusStateCompleted:
+		p.FinishedAt = suite.UpdatedAt.Time
+	case githubv4.CheckStatusStateInProgress:
+		p.Status = vcs.PipelineRunning
+		return p
+	default:
+		p.Status = vcs.PipelinePending
+		return p
+	}
+
+	switch suite.Conclusion {
+	case githubv4.CheckConclusionStateSuccess, githubv4.CheckConclusionStateNeutral:
+		p.Status = vcs.PipelineSuccess
+	case githubv4.CheckConclusionStateCancelled, githubv4.CheckConclusionStateStale:
+		p.Status = vcs.PipelineCanceled
+	case githubv4.CheckConclusionStateSkipped:
+		p.Status = vcs.PipelineSkipped
+	default:
+		p.Status = vcs.PipelineFailed
+	}
+
+	return p
+}
@@ -2,12 +2,14 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/muesli/gitty/vcs"
 	"github.com/shurcooL/githubv4"
 )
 
-var pullRequestQuery struct {
+type pullRequestQuery struct {
 	Repository struct {
 		PullRequests struct {
 			TotalCount githubv4.Int
@@ -17,7 +19,7 @@ var pullRequestQuery struct {
 					qlPullRequest
 				}
 			}
-		} `graphql:"pullRequests(first: 100, after: $after, states: OPEN, orderBy: {field: CREATED_AT, direction: DESC})"`
+		} `graphql:"pullRequests(first: $first, after: $after, states: OPEN, orderBy: $orderBy)"`
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
@@ -25,8 +27,13 @@ type qlPullRequest struct {
 	Number    githubv4.Int
 	Body      githubv4.String
 	Title     githubv4.String
+	State     githubv4.String
 	CreatedAt githubv4.DateTime
-	Labels    struct {
+	URL       githubv4.String
+	Author    struct {
+		Login githubv4.String
+	}
+	Labels struct {
 		Edges []struct {
 			Cursor githubv4.String
 			Node   struct {
@@ -37,32 +44,40 @@ type qlPullRequest struct {
 	} `graphql:"labels(first: 100, orderBy: {field: NAME, direction: ASC})"`
 }
 
-// PullRequests returns a list of pull requests for the given repository.
-func (c *Client) PullRequests(owner string, name string) ([]vcs.PullRequest, error) {
+// PullRequests returns a list of pull requests for the given repository and
+// their total amount.
+func (c *Client) PullRequests(owner string, name string, opts vcs.ListOptions) ([]vcs.PullRequest, int, error) {
+	var query pullRequestQuery
 	var pullRequests []vcs.PullRequest
 
 	variables := map[string]interface{}{
-		"owner": githubv4.String(owner),
-		"name":  githubv4.String(name),
-		"after": (*githubv4.String)(nil),
+		"owner":   githubv4.String(owner),
+		"name":    githubv4.String(name),
+		"first":   githubv4.Int(opts.PerPage(100)),
+		"orderBy": issueOrder(opts.Sort),
+		"after":   (*githubv4.String)(nil),
 	}
 
 	for {
-		if err := c.queryWithRetry(context.Background(), &pullRequestQuery, variables); err != nil {
-			return pullRequests, err
+		if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+			return pullRequests, 0, err
 		}
-		if len(pullRequestQuery.Repository.PullRequests.Edges) == 0 {
+		if len(query.Repository.PullRequests.Edges) == 0 {
 			break
 		}
 
-		for _, v := range pullRequestQuery.Repository.PullRequests.Edges {
+		for _, v := range query.Repository.PullRequests.Edges {
 			pullRequests = append(pullRequests, pullRequestFromQL(v.Node.qlPullRequest))
 
 			variables["after"] = githubv4.NewString(v.Cursor)
 		}
+		if opts.Done(len(pullRequests)) {
+			pullRequests = pullRequests[:opts.Limit]
+			break
+		}
 	}
 
-	return pullRequests, nil
+	return pullRequests, int(query.Repository.PullRequests.TotalCount), nil
 }
 
 func pullRequestFromQL(pr qlPullRequest) vcs.PullRequest {
@@ -70,7 +85,10 @@ func pullRequestFromQL(pr qlPullRequest) vcs.PullRequest {
 		ID:        int(pr.Number),
 		Body:      string(pr.Body),
 		Title:     string(pr.Title),
+		Author:    string(pr.Author.Login),
+		State:     strings.ToLower(string(pr.State)),
 		CreatedAt: pr.CreatedAt.Time,
+		URL:       string(pr.URL),
 	}
 
 	for _, v := range pr.Labels.Edges {
@@ -82,3 +100,127 @@ func pullRequestFromQL(pr qlPullRequest) vcs.PullRequest {
 
 	return p
 }
+
+type qlPullRequestStatus struct {
+	qlPullRequest
+	HeadRefName    githubv4.String
+	HeadRepository *struct {
+		NameWithOwner githubv4.String
+		URL           githubv4.String
+	}
+	IsDraft        githubv4.Boolean
+	ReviewDecision githubv4.String
+	Mergeable      githubv4.String
+	Comments       struct {
+		TotalCount githubv4.Int
+	}
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State githubv4.String
+				}
+			}
+		}
+	} `graphql:"commits(last: 1)"`
+}
+
+type branchPullRequestQuery struct {
+	Repository struct {
+		PullRequests struct {
+			Nodes []qlPullRequestStatus
+		} `graphql:"pullRequests(first: 10, states: OPEN, headRefName: $branch, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type singlePullRequestQuery struct {
+	Repository struct {
+		PullRequest *qlPullRequestStatus `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// PullRequestForBranch returns the newest open pull request of the given
+// branch from a repository owned by one of headOwners, or nil if there is
+// none.
+func (c *Client) PullRequestForBranch(owner string, name string, branch string, headOwners []string) (*vcs.PullRequestStatus, error) {
+	var query branchPullRequestQuery
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"branch": githubv4.String(branch),
+	}
+
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return nil, err
+	}
+
+	// forks may use the same branch name
+	for _, v := range query.Repository.PullRequests.Nodes {
+		if pr := pullRequestStatusFromQL(v); vcs.OwnedBy(pr.HeadRepo, headOwners) {
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
+
+// PullRequest returns the pull request with the given number.
+func (c *Client) PullRequest(owner string, name string, number int) (*vcs.PullRequestStatus, error) {
+	var query singlePullRequestQuery
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+	}
+
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return nil, notFound(err, "pull request", number)
+	}
+	if query.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %d %w", number, vcs.ErrNotFound)
+	}
+
+	return pullRequestStatusFromQL(*query.Repository.PullRequest), nil
+}
+
+func pullRequestStatusFromQL(v qlPullRequestStatus) *vcs.PullRequestStatus {
+	pr := &vcs.PullRequestStatus{
+		PullRequest: pullRequestFromQL(v.qlPullRequest),
+		Branch:      string(v.HeadRefName),
+		Draft:       bool(v.IsDraft),
+		Comments:    int(v.Comments.TotalCount),
+		Mergeable:   vcs.MergeUnknown,
+	}
+	if v.HeadRepository != nil {
+		pr.HeadRepo = string(v.HeadRepository.NameWithOwner)
+		pr.HeadCloneURL = string(v.HeadRepository.URL) + ".git"
+	}
+
+	switch v.ReviewDecision {
+	case "APPROVED":
+		pr.Review = vcs.ReviewApproved
+	case "CHANGES_REQUESTED":
+		pr.Review = vcs.ReviewChangesRequested
+	case "REVIEW_REQUIRED":
+		pr.Review = vcs.ReviewRequired
+	}
+
+	switch v.Mergeable {
+	case "MERGEABLE":
+		pr.Mergeable = vcs.MergeClean
+	case "CONFLICTING":
+		pr.Mergeable = vcs.MergeConflicting
+	}
+
+	if len(v.Commits.Nodes) > 0 && v.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		switch v.Commits.Nodes[0].Commit.StatusCheckRollup.State {
+		case "SUCCESS":
+			pr.CI = vcs.PipelineSuccess
+		case "FAILURE", "ERROR":
+			pr.CI = vcs.PipelineFailed
+		default:
+			pr.CI = vcs.PipelinePending
+		}
+	}
+
+	return pr
+}
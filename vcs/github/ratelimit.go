@@ -0,0 +1,79 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+// errRateLimited is returned for GraphQL queries failing with a RATE_LIMITED
+// error.
+var errRateLimited = errors.New("graphql rate limit exceeded")
+
+// graphQLErrorTransport turns GraphQL responses reporting a RATE_LIMITED
+// error into errRateLimited. The GraphQL client only exposes the message of
+// errors, not their type.
+type graphQLErrorTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *graphQLErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK ||
+		!strings.HasSuffix(req.URL.Path, "/graphql") {
+		return resp, err
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint:errcheck
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	var out struct {
+		Errors []struct {
+			Type string
+		}
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		// let the GraphQL client deal with it
+		return resp, nil //nolint:nilerr
+	}
+	for _, v := range out.Errors {
+		if v.Type == "RATE_LIMITED" {
+			return nil, errRateLimited
+		}
+	}
+	return resp, nil
+}
+
+type rateLimitQuery struct {
+	RateLimit struct {
+		Limit     githubv4.Int
+		Remaining githubv4.Int
+		ResetAt   githubv4.DateTime
+	}
+}
+
+// RateLimit returns the current GraphQL API quota.
+func (c *Client) RateLimit() (vcs.RateLimit, error) {
+	var query rateLimitQuery
+	if err := c.queryWithRetry(context.Background(), &query, nil); err != nil {
+		return vcs.RateLimit{}, err
+	}
+
+	return vcs.RateLimit{
+		Limit:     int(query.RateLimit.Limit),
+		Remaining: int(query.RateLimit.Remaining),
+		Reset:     query.RateLimit.ResetAt.Time,
+	}, nil
+}
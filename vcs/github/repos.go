@@ -7,7 +7,7 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
-var reposQuery struct {
+type reposQuery struct {
 	User struct {
 		Login        githubv4.String
 		Repositories struct {
@@ -22,7 +22,7 @@ var reposQuery struct {
 	} `graphql:"repositoryOwner(login:$username)"`
 }
 
-var repoQuery struct {
+type repoQuery struct {
 	Repository qlRepository `graphql:"repository(owner: $owner, name: $name)"`
 }
 
@@ -30,10 +30,13 @@ type qlRepository struct {
 	Owner struct {
 		Login githubv4.String
 	}
-	Name           githubv4.String
-	NameWithOwner  githubv4.String
-	URL            githubv4.String
-	Description    githubv4.String
+	Name             githubv4.String
+	NameWithOwner    githubv4.String
+	URL              githubv4.String
+	Description      githubv4.String
+	DefaultBranchRef struct {
+		Name githubv4.String
+	}
 	IsPrivate      githubv4.Boolean
 	ForkCount      githubv4.Int
 	StargazerCount githubv4.Int
@@ -55,18 +58,20 @@ type qlRepository struct {
 
 // Repository returns the repository with the given name.
 func (c *Client) Repository(owner string, name string) (vcs.Repo, error) {
+	var query repoQuery
+
 	variables := map[string]interface{}{
 		"owner": githubv4.String(owner),
 		"name":  githubv4.String(name),
 	}
 
-	if err := c.queryWithRetry(context.Background(), &repoQuery, variables); err != nil {
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
 		return vcs.Repo{}, err
 	}
 
-	repo := repoFromQL(repoQuery.Repository)
-	if len(repoQuery.Repository.Releases.Nodes) > 0 {
-		repo.LastRelease = releaseFromQL(repoQuery.Repository.Releases)
+	repo := repoFromQL(query.Repository)
+	if len(query.Repository.Releases.Nodes) > 0 {
+		repo.LastRelease = releaseFromQL(query.Repository.Releases)
 	}
 
 	return repo, nil
@@ -74,6 +79,7 @@ func (c *Client) Repository(owner string, name string) (vcs.Repo, error) {
 
 // Repositories returns a list of repositories for the given user.
 func (c *Client) Repositories(owner string) ([]vcs.Repo, error) {
+	var query reposQuery
 	var repos []vcs.Repo
 
 	variables := map[string]interface{}{
@@ -82,14 +88,14 @@ func (c *Client) Repositories(owner string) ([]vcs.Repo, error) {
 	}
 
 	for {
-		if err := c.queryWithRetry(context.Background(), &reposQuery, variables); err != nil {
+		if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
 			return nil, err
 		}
-		if len(reposQuery.User.Repositories.Edges) == 0 {
+		if len(query.User.Repositories.Edges) == 0 {
 			break
 		}
 
-		for _, v := range reposQuery.User.Repositories.Edges {
+		for _, v := range query.User.Repositories.Edges {
 			repo := repoFromQL(v.Node.qlRepository)
 			if len(v.Node.Releases.Nodes) > 0 {
 				repo.LastRelease = releaseFromQL(v.Node.Releases)
@@ -110,6 +116,7 @@ func repoFromQL(repo qlRepository) vcs.Repo {
 		Name:          string(repo.Name),
 		NameWithOwner: string(repo.NameWithOwner),
 		URL:           string(repo.URL),
+		DefaultBranch: string(repo.DefaultBranchRef.Name),
 		Description:   string(repo.Description),
 		Stargazers:    int(repo.StargazerCount),
 		Watchers:      int(repo.Watchers.TotalCount),
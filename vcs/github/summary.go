@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/shurcooL/githubv4"
+)
+
+type summaryQuery struct {
+	Repository struct {
+		qlRepository
+		Issues struct {
+			TotalCount githubv4.Int
+			Nodes      []qlIssue
+		} `graphql:"issues(first: $issues, states: OPEN, orderBy: {field: CREATED_AT, direction: DESC})"`
+		PullRequests struct {
+			TotalCount githubv4.Int
+			Nodes      []qlPullRequest
+		} `graphql:"pullRequests(first: $pullRequests, states: OPEN, orderBy: {field: CREATED_AT, direction: DESC})"`
+		Refs struct {
+			Nodes []struct {
+				Name   githubv4.String
+				Target struct {
+					Commit qlCommit `graphql:"... on Commit"`
+				}
+			}
+		} `graphql:"refs(first: 100, refPrefix: \"refs/heads/\", orderBy: {field: TAG_COMMIT_DATE, direction: DESC})"`
+		Milestones struct {
+			Nodes []qlMilestone
+		} `graphql:"milestones(first: 100, states: OPEN, orderBy: {field: DUE_DATE, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// Summary returns the repository with its latest issues and pull requests, up
+// to 100 each, its 100 most recently updated branches and its open milestones
+// in a single query.
+func (c *Client) Summary(owner string, name string, maxIssues, maxPullRequests int) (vcs.Summary, error) {
+	if maxIssues <= 0 || maxIssues > 100 {
+		maxIssues = 100
+	}
+	if maxPullRequests <= 0 || maxPullRequests > 100 {
+		maxPullRequests = 100
+	}
+
+	var query summaryQuery
+	variables := map[string]interface{}{
+		"owner":        githubv4.String(owner),
+		"name":         githubv4.String(name),
+		"issues":       githubv4.Int(maxIssues),
+		"pullRequests": githubv4.Int(maxPullRequests),
+	}
+	if err := c.queryWithRetry(context.Background(), &query, variables); err != nil {
+		return vcs.Summary{}, err
+	}
+
+	r := query.Repository
+	s := vcs.Summary{
+		Repo:             repoFromQL(r.qlRepository),
+		IssueCount:       int(r.Issues.TotalCount),
+		PullRequestCount: int(r.PullRequests.TotalCount),
+	}
+	if len(r.Releases.Nodes) > 0 {
+		s.Repo.LastRelease = releaseFromQL(r.Releases)
+	}
+
+	for _, v := range r.Issues.Nodes {
+		s.Issues = append(s.Issues, issueFromQL(v))
+	}
+	for _, v := range r.PullRequests.Nodes {
+		s.PullRequests = append(s.PullRequests, pullRequestFromQL(v))
+	}
+	for _, v := range r.Refs.Nodes {
+		s.Branches = append(s.Branches, vcs.Branch{
+			Name:       string(v.Name),
+			LastCommit: commitFromQL(v.Target.Commit),
+			URL:        c.BranchURL(owner, name, string(v.Name)),
+		})
+	}
+	for _, v := range r.Milestones.Nodes {
+		s.Milestones = append(s.Milestones, milestoneFromQL(v))
+	}
+
+	// GitHub lists milestones without a due date first
+	vcs.SortMilestones(s.Milestones)
+	return s, nil
+}
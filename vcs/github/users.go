@@ -15,7 +15,7 @@ type qlUser struct {
 }
 */
 
-var viewerQuery struct {
+type viewerQuery struct {
 	Viewer struct {
 		Login githubv4.String
 	}
@@ -23,11 +23,13 @@ var viewerQuery struct {
 
 // GetUsername returns the username of the authenticated user.
 func (c *Client) GetUsername() (string, error) {
-	if err := c.queryWithRetry(context.Background(), &viewerQuery, nil); err != nil {
+	var query viewerQuery
+
+	if err := c.queryWithRetry(context.Background(), &query, nil); err != nil {
 		return "", err
 	}
 
-	return string(viewerQuery.Viewer.Login), nil
+	return string(query.Viewer.Login), nil
 }
 
 /*
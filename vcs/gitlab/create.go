@@ -0,0 +1,130 @@
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// userIDs looks up the IDs of the given users.
+func (c *Client) userIDs(usernames []string) (*[]int, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	var ids []int
+	for _, u := range usernames {
+		users, _, err := c.api.Users.ListUsers(&gitlab.ListUsersOptions{
+			Username: gitlab.String(u),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("unknown user: %s", u)
+		}
+		ids = append(ids, users[0].ID)
+	}
+	return &ids, nil
+}
+
+// milestoneID looks up the ID of the milestone with the given title.
+func (c *Client) milestoneID(pid string, title string) (*int, error) {
+	if title == "" {
+		return nil, nil
+	}
+
+	ms, _, err := c.api.Milestones.ListMilestones(pid, &gitlab.ListMilestonesOptions{
+		Title: gitlab.String(title),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, fmt.Errorf("unknown milestone: %s", title)
+	}
+	return &ms[0].ID, nil
+}
+
+// labels returns the label option of a new issue or merge request.
+func labels(ll []string) *gitlab.Labels {
+	if len(ll) == 0 {
+		return nil
+	}
+	l := gitlab.Labels(ll)
+	return &l
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(owner string, name string, issue vcs.NewIssue) (*vcs.Issue, error) {
+	pid := owner + "/" + name
+	assignees, err := c.userIDs(issue.Assignees)
+	if err != nil {
+		return nil, err
+	}
+	milestone, err := c.milestoneID(pid, issue.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := c.api.Issues.CreateIssue(pid, &gitlab.CreateIssueOptions{
+		Title:       gitlab.String(issue.Title),
+		Description: gitlab.String(issue.Body),
+		Labels:      labels(issue.Labels),
+		AssigneeIDs: assignees,
+		MilestoneID: milestone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i := c.issueFromAPI(v)
+	return &i, nil
+}
+
+// CreatePullRequest creates a new merge request.
+func (c *Client) CreatePullRequest(owner string, name string, pr vcs.NewPullRequest) (*vcs.PullRequest, error) {
+	pid := owner + "/" + name
+	assignees, err := c.userIDs(pr.Assignees)
+	if err != nil {
+		return nil, err
+	}
+	milestone, err := c.milestoneID(pid, pr.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, _, err := c.api.MergeRequests.CreateMergeRequest(pid, &gitlab.CreateMergeRequestOptions{
+		Title:        gitlab.String(pr.Title),
+		Description:  gitlab.String(pr.Body),
+		SourceBranch: gitlab.String(pr.Head),
+		TargetBranch: gitlab.String(pr.Base),
+		Labels:       labels(pr.Labels),
+		AssigneeIDs:  assignees,
+		MilestoneID:  milestone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &vcs.PullRequest{
+		ID:        mr.IID,
+		Body:      mr.Description,
+		Title:     mr.Title,
+		State:     state(mr.State),
+		CreatedAt: *mr.CreatedAt,
+		URL:       mr.WebURL,
+	}
+	if mr.Author != nil {
+		p.Author = mr.Author.Username
+	}
+	for _, l := range mr.Labels {
+		p.Labels = append(p.Labels, vcs.Label{
+			Name:  l,
+			Color: c.colorForLabel(l),
+		})
+	}
+
+	return p, nil
+}
@@ -0,0 +1,89 @@
+package gitlab
+
+import (
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// referenceRepo returns the project path of a full reference like
+// group/project#42 or group/project!42.
+func referenceRepo(refs *gitlab.IssueReferences) string {
+	if refs == nil {
+		return ""
+	}
+	if i := strings.LastIndexAny(refs.Full, "#!"); i >= 0 {
+		return refs.Full[:i]
+	}
+	return refs.Full
+}
+
+// maxDashboardPullRequests caps the merge requests listed per dashboard
+// section, as the status of each of them costs two or three more requests.
+const maxDashboardPullRequests = 20
+
+// userPullRequests returns the open merge requests matching opts, along with
+// their status. Merge requests whose status can't be retrieved are left out.
+func (c *Client) userPullRequests(opts *gitlab.ListMergeRequestsOptions) ([]vcs.UserPullRequest, error) {
+	opts.State = gitlab.String("opened")
+	opts.Scope = gitlab.String("all")
+	opts.OrderBy = gitlab.String("updated_at")
+	opts.PerPage = maxDashboardPullRequests
+
+	mrs, _, err := c.api.MergeRequests.ListMergeRequests(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []vcs.UserPullRequest
+	for _, v := range mrs {
+		repo := referenceRepo(v.References)
+		pr, err := c.pullRequestStatus(repo, v.IID)
+		if err != nil {
+			// e.g. the project became inaccessible
+			continue
+		}
+		prs = append(prs, vcs.UserPullRequest{
+			PullRequestStatus: *pr,
+			Repo:              repo,
+		})
+	}
+	return prs, nil
+}
+
+// Dashboard returns the open issues assigned to a user, the merge requests
+// awaiting their review, and their own open merge requests.
+func (c *Client) Dashboard(username string) (vcs.Dashboard, error) {
+	var d vcs.Dashboard
+
+	issues, _, err := c.api.Issues.ListIssues(&gitlab.ListIssuesOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 50,
+		},
+		State:            gitlab.String("opened"),
+		Scope:            gitlab.String("all"),
+		AssigneeUsername: gitlab.String(username),
+		OrderBy:          gitlab.String("updated_at"),
+	})
+	if err != nil {
+		return d, err
+	}
+	for _, v := range issues {
+		d.Assigned = append(d.Assigned, vcs.AssignedIssue{
+			Issue: c.issueFromAPI(v),
+			Repo:  referenceRepo(v.References),
+		})
+	}
+
+	d.ReviewRequests, err = c.userPullRequests(&gitlab.ListMergeRequestsOptions{
+		ReviewerUsername: gitlab.String(username),
+	})
+	if err != nil {
+		return d, err
+	}
+	d.Authored, err = c.userPullRequests(&gitlab.ListMergeRequestsOptions{
+		AuthorUsername: gitlab.String(username),
+	})
+	return d, err
+}
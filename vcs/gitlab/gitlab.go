@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -15,6 +16,7 @@ import (
 // Client is a client for GitLab.
 type Client struct {
 	api         *gitlab.Client
+	transport   *vcs.RateLimitTransport
 	host        string
 	colors      map[string]int
 	labelColors map[string]string
@@ -29,7 +31,12 @@ func NewClient(baseURL, token string, preverified bool) (*Client, error) {
 	u.Path = path.Join(u.Path, "/api/v4")
 	u.Scheme = "https"
 
-	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(u.String()))
+	// backing off is left to our own transport
+	transport := vcs.NewRateLimitTransport(nil)
+	client, err := gitlab.NewClient(token,
+		gitlab.WithBaseURL(u.String()),
+		gitlab.WithHTTPClient(&http.Client{Transport: transport}),
+		gitlab.WithoutRetries())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %v", err)
 	}
@@ -43,12 +50,23 @@ func NewClient(baseURL, token string, preverified bool) (*Client, error) {
 
 	return &Client{
 		api:         client,
+		transport:   transport,
 		host:        baseURL,
 		colors:      map[string]int{},
 		labelColors: map[string]string{},
 	}, nil
 }
 
+// RateLimit returns the current API quota.
+func (c *Client) RateLimit() (vcs.RateLimit, error) {
+	// GitLab reports the quota with every response
+	if _, _, err := c.api.Version.GetVersion(); err != nil {
+		return vcs.RateLimit{}, err
+	}
+
+	return c.transport.RateLimit(), nil
+}
+
 // GetUsername returns the username of the authenticated user.
 func (c *Client) GetUsername() (string, error) {
 	u, _, err := c.api.Users.CurrentUser()
@@ -59,29 +77,45 @@ func (c *Client) GetUsername() (string, error) {
 	return u.Username, nil
 }
 
-// Issues returns a list of issues for the given repository.
-func (c *Client) Issues(owner string, name string) ([]vcs.Issue, error) {
+// Issues returns a list of issues for the given repository and their total
+// amount.
+func (c *Client) Issues(owner string, name string, opts vcs.ListOptions) ([]vcs.Issue, int, error) {
 	var i []vcs.Issue
+	var total int
+
+	listOpts := &gitlab.ListProjectIssuesOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    1,
+			PerPage: opts.PerPage(100),
+		},
+		State:   gitlab.String("opened"),
+		OrderBy: gitlab.String(orderBy(opts.Sort)),
+		Sort:    gitlab.String("desc"),
+	}
+	if opts.Milestone != "" {
+		listOpts.Milestone = gitlab.String(opts.Milestone)
+	}
 
-	page := 1
 	for {
-		issues, resp, err := c.api.Issues.ListProjectIssues(owner+"/"+name,
-			&gitlab.ListProjectIssuesOptions{
-				ListOptions: gitlab.ListOptions{
-					Page:    page,
-					PerPage: 250,
-				},
-				State: gitlab.String("opened"),
-			})
+		issues, resp, err := c.api.Issues.ListProjectIssues(owner+"/"+name, listOpts)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		total = resp.TotalItems
 
 		for _, v := range issues {
 			issue := vcs.Issue{
 				ID:        v.IID,
 				Title:     v.Title,
+				State:     state(v.State),
 				CreatedAt: *v.CreatedAt,
+				URL:       v.WebURL,
+			}
+			if v.Author != nil {
+				issue.Author = v.Author.Username
+			}
+			if v.Milestone != nil {
+				issue.Milestone = v.Milestone.Title
 			}
 			for _, l := range v.Labels {
 				issue.Labels = append(issue.Labels, vcs.Label{
@@ -92,18 +126,24 @@ func (c *Client) Issues(owner string, name string) ([]vcs.Issue, error) {
 			i = append(i, issue)
 		}
 
-		page++
-		if resp.TotalPages == page || len(issues) == 0 {
+		listOpts.Page = resp.NextPage
+		if listOpts.Page == 0 || len(issues) == 0 || opts.Done(len(i)) {
 			break
 		}
 	}
 
-	return i, nil
+	more := listOpts.Page != 0 || (opts.Done(len(i)) && len(i) > opts.Limit)
+	if opts.Done(len(i)) {
+		i = i[:opts.Limit]
+	}
+	return i, vcs.Total(total, len(i), more), nil
 }
 
-// PullRequests returns a list of pull requests for the given repository.
-func (c *Client) PullRequests(owner string, name string) ([]vcs.PullRequest, error) {
+// PullRequests returns a list of pull requests for the given repository and
+// their total amount.
+func (c *Client) PullRequests(owner string, name string, opts vcs.ListOptions) ([]vcs.PullRequest, int, error) {
 	var i []vcs.PullRequest
+	var total int
 
 	page := 1
 	for {
@@ -111,19 +151,27 @@ func (c *Client) PullRequests(owner string, name string) ([]vcs.PullRequest, err
 			&gitlab.ListProjectMergeRequestsOptions{
 				ListOptions: gitlab.ListOptions{
 					Page:    page,
-					PerPage: 250,
+					PerPage: opts.PerPage(100),
 				},
-				State: gitlab.String("opened"),
+				State:   gitlab.String("opened"),
+				OrderBy: gitlab.String(orderBy(opts.Sort)),
+				Sort:    gitlab.String("desc"),
 			})
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		total = resp.TotalItems
 
 		for _, v := range prs {
 			pr := vcs.PullRequest{
 				ID:        v.IID,
 				Title:     v.Title,
+				State:     state(v.State),
 				CreatedAt: *v.CreatedAt,
+				URL:       v.WebURL,
+			}
+			if v.Author != nil {
+				pr.Author = v.Author.Username
 			}
 			for _, l := range v.Labels {
 				pr.Labels = append(pr.Labels, vcs.Label{
@@ -134,13 +182,17 @@ func (c *Client) PullRequests(owner string, name string) ([]vcs.PullRequest, err
 			i = append(i, pr)
 		}
 
-		page++
-		if resp.TotalPages == page || len(prs) == 0 {
+		page = resp.NextPage
+		if page == 0 || len(prs) == 0 || opts.Done(len(i)) {
 			break
 		}
 	}
 
-	return i, nil
+	more := page != 0 || (opts.Done(len(i)) && len(i) > opts.Limit)
+	if opts.Done(len(i)) {
+		i = i[:opts.Limit]
+	}
+	return i, vcs.Total(total, len(i), more), nil
 }
 
 // Repository returns the repository with the given name.
@@ -205,17 +257,25 @@ func (c *Client) Repositories(owner string) ([]vcs.Repo, error) {
 	return repos, nil //nolint
 }
 
-// Branches returns a list of branches for the given repository.
-func (c *Client) Branches(owner string, name string) ([]vcs.Branch, error) {
+// Branches returns a list of branches for the given repository. Sorted by
+// SortUpdated, the most recently updated branches come first.
+func (c *Client) Branches(owner string, name string, opts vcs.ListOptions) ([]vcs.Branch, error) {
 	var i []vcs.Branch
-	opts := &gitlab.ListBranchesOptions{
+	lo := &gitlab.ListBranchesOptions{
 		ListOptions: gitlab.ListOptions{
-			PerPage: 250,
+			PerPage: opts.PerPage(100),
 		},
 	}
-	for {
-		opts.Page++
-		branches, _, err := c.api.Branches.ListBranches(owner+"/"+name, opts)
+
+	// go-gitlab doesn't support sorting branches yet
+	var sort []gitlab.RequestOptionFunc
+	if opts.Sort == vcs.SortUpdated {
+		sort = append(sort, withQuery("sort", "updated_desc"))
+	}
+
+	for !opts.Done(len(i)) {
+		lo.Page++
+		branches, _, err := c.api.Branches.ListBranches(owner+"/"+name, lo, sort...)
 		if err != nil {
 			return nil, err
 		}
@@ -231,12 +291,17 @@ func (c *Client) Branches(owner string, name string) ([]vcs.Branch, error) {
 					MessageHeadline: v.Commit.Title,
 					CommittedAt:     *v.Commit.CommittedDate,
 					Author:          v.Commit.CommitterName,
+					URL:             v.Commit.WebURL,
 				},
+				URL: v.WebURL,
 			}
 			i = append(i, branch)
 		}
 	}
 
+	if opts.Done(len(i)) {
+		i = i[:opts.Limit]
+	}
 	return i, nil
 }
 
@@ -249,7 +314,7 @@ func (c *Client) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit,
 		opt := gitlab.ListCommitsOptions{
 			ListOptions: gitlab.ListOptions{
 				Page:    page,
-				PerPage: 250,
+				PerPage: vcs.ListOptions{Limit: max}.PerPage(100),
 			},
 		}
 		if !since.IsZero() {
@@ -266,11 +331,12 @@ func (c *Client) History(repo vcs.Repo, max int, since time.Time) ([]vcs.Commit,
 				MessageHeadline: strings.ReplaceAll(v.Title, "\u00A0", " "),
 				CommittedAt:     *v.CommittedDate,
 				Author:          v.AuthorName,
+				URL:             v.WebURL,
 			})
 		}
 
-		page++
-		if resp.TotalPages == page || len(h) == 0 {
+		page = resp.NextPage
+		if page == 0 || len(h) == 0 {
 			break
 		}
 		if max > 0 && len(commits) >= max {
@@ -304,6 +370,7 @@ func (c *Client) repoFromAPI(p *gitlab.Project) vcs.Repo {
 			Name:        r[0].Name,
 			TagName:     r[0].TagName,
 			PublishedAt: *r[0].CreatedAt,
+			URL:         p.WebURL + "/-/releases/" + url.PathEscape(r[0].TagName),
 		}
 	}
 
@@ -312,6 +379,7 @@ func (c *Client) repoFromAPI(p *gitlab.Project) vcs.Repo {
 		Name:          p.Name,
 		NameWithOwner: p.PathWithNamespace,
 		URL:           p.WebURL,
+		DefaultBranch: p.DefaultBranch,
 		Description:   p.Description,
 		Stargazers:    p.StarCount,
 		Watchers:      0,
@@ -351,3 +419,380 @@ func (c *Client) colorForLabel(label string) string {
 
 	return c.labelColors[label]
 }
+
+// Pipelines returns the latest CI pipelines of the given branches.
+func (c *Client) Pipelines(owner string, name string, branches []string) ([]vcs.Pipeline, error) {
+	var pipelines []vcs.Pipeline //nolint
+	pid := owner + "/" + name
+
+	for _, branch := range branches {
+		p, _, err := c.api.Pipelines.ListProjectPipelines(pid, &gitlab.ListProjectPipelinesOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 1,
+			},
+			Ref:     gitlab.String(branch),
+			OrderBy: gitlab.String("id"),
+			Sort:    gitlab.String("desc"),
+		})
+		if err != nil {
+			return pipelines, err
+		}
+		if len(p) == 0 {
+			continue
+		}
+
+		pl, _, err := c.api.Pipelines.GetPipeline(pid, p[0].ID)
+		if err != nil {
+			return pipelines, err
+		}
+
+		pipeline := vcs.Pipeline{
+			Branch: branch,
+			Name:   fmt.Sprintf("#%d", pl.ID),
+			Status: pipelineStatus(pl.Status),
+			URL:    pl.WebURL,
+		}
+		if pl.StartedAt != nil {
+			pipeline.StartedAt = *pl.StartedAt
+		}
+		if pl.FinishedAt != nil {
+			pipeline.FinishedAt = *pl.FinishedAt
+		}
+
+		if pipeline.Status == vcs.PipelineFailed {
+			jobs, _, err := c.api.Jobs.ListPipelineJobs(pid, pl.ID, &gitlab.ListJobsOptions{
+				ListOptions: gitlab.ListOptions{
+					PerPage: 1,
+				},
+				Scope: &[]gitlab.BuildStateValue{gitlab.Failed},
+			})
+			if err == nil && len(jobs) > 0 {
+				pipeline.FailedJob = jobs[0].Name
+			}
+		}
+
+		pipelines = append(pipelines, pipeline)
+	}
+
+	return pipelines, nil
+}
+
+func pipelineStatus(status string) vcs.PipelineStatus {
+	switch status {
+	case "success":
+		return vcs.PipelineSuccess
+	case "failed":
+		return vcs.PipelineFailed
+	case "running":
+		return vcs.PipelineRunning
+	case "canceled":
+		return vcs.PipelineCanceled
+	case "skipped":
+		return vcs.PipelineSkipped
+	default:
+		// created, waiting_for_resource, preparing, pending, manual, scheduled
+		return vcs.PipelinePending
+	}
+}
+
+// maxMilestones limits how many milestones get listed, as each of them needs
+// a request for its issue counts.
+const maxMilestones = 20
+
+// Milestones returns the active milestones of the given repository that are
+// due next.
+func (c *Client) Milestones(owner string, name string) ([]vcs.Milestone, error) {
+	pid := owner + "/" + name
+	ms, _, err := c.api.Milestones.ListMilestones(pid, &gitlab.ListMilestonesOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		State: gitlab.String("active"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var milestones []vcs.Milestone //nolint
+	for _, v := range ms {
+		m := vcs.Milestone{
+			ID:    v.IID,
+			Title: v.Title,
+			URL:   v.WebURL,
+		}
+		if v.DueDate != nil {
+			m.DueOn = time.Time(*v.DueDate)
+		}
+		milestones = append(milestones, m)
+	}
+
+	vcs.SortMilestones(milestones)
+	if len(milestones) > maxMilestones {
+		milestones = milestones[:maxMilestones]
+	}
+
+	// GitLab doesn't report issue counts with milestones, but the statistics
+	// of the issues matching a filter
+	for i, m := range milestones {
+		stats, _, err := c.api.IssuesStatistics.GetProjectIssuesStatistics(pid,
+			&gitlab.GetProjectIssuesStatisticsOptions{
+				Milestone: gitlab.String(m.Title),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		milestones[i].OpenIssues = stats.Statistics.Counts.Opened
+		milestones[i].ClosedIssues = stats.Statistics.Counts.Closed
+	}
+
+	return milestones, nil
+}
+
+// PullRequestForBranch returns the newest open merge request of the given
+// branch from a project owned by one of headOwners, or nil if there is none.
+func (c *Client) PullRequestForBranch(owner string, name string, branch string, headOwners []string) (*vcs.PullRequestStatus, error) {
+	pid := owner + "/" + name
+	mrs, _, err := c.api.MergeRequests.ListProjectMergeRequests(pid,
+		&gitlab.ListProjectMergeRequestsOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 10,
+			},
+			State:        gitlab.String("opened"),
+			SourceBranch: gitlab.String(branch),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// forks may use the same branch name. Only a single merge request
+	// contains its head pipeline and source project.
+	for _, v := range mrs {
+		pr, err := c.pullRequestStatus(pid, v.IID)
+		if err != nil {
+			return nil, err
+		}
+		if vcs.OwnedBy(pr.HeadRepo, headOwners) {
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
+
+// PullRequest returns the merge request with the given number.
+func (c *Client) PullRequest(owner string, name string, number int) (*vcs.PullRequestStatus, error) {
+	return c.pullRequestStatus(owner+"/"+name, number)
+}
+
+func (c *Client) pullRequestStatus(pid string, iid int) (*vcs.PullRequestStatus, error) {
+	mr, resp, err := c.api.MergeRequests.GetMergeRequest(pid, iid, nil)
+	if err != nil {
+		return nil, notFound(err, resp, "merge request", iid)
+	}
+
+	pr := &vcs.PullRequestStatus{
+		PullRequest: vcs.PullRequest{
+			ID:        mr.IID,
+			Body:      mr.Description,
+			Title:     mr.Title,
+			State:     state(mr.State),
+			CreatedAt: *mr.CreatedAt,
+			URL:       mr.WebURL,
+		},
+		Branch:    mr.SourceBranch,
+		Draft:     mr.Draft,
+		Comments:  mr.UserNotesCount,
+		Mergeable: vcs.MergeUnknown,
+	}
+	if mr.Author != nil {
+		pr.Author = mr.Author.Username
+	}
+	for _, l := range mr.Labels {
+		pr.Labels = append(pr.Labels, vcs.Label{
+			Name:  l,
+			Color: c.colorForLabel(l),
+		})
+	}
+
+	switch {
+	case mr.HasConflicts:
+		pr.Mergeable = vcs.MergeConflicting
+	case mr.MergeStatus == "can_be_merged":
+		pr.Mergeable = vcs.MergeClean
+	}
+
+	if mr.HeadPipeline != nil {
+		pr.CI = pipelineStatus(mr.HeadPipeline.Status)
+	}
+
+	// merge requests from forks come from another project
+	if mr.SourceProjectID == mr.TargetProjectID {
+		pr.HeadRepo = pid
+		pr.HeadCloneURL = fmt.Sprintf("https://%s/%s.git", c.host, pid)
+	} else if src, _, err := c.api.Projects.GetProject(mr.SourceProjectID, nil); err == nil {
+		pr.HeadRepo = src.PathWithNamespace
+		pr.HeadCloneURL = src.HTTPURLToRepo
+	}
+
+	// approvals are a paid feature on some instances, so ignore errors
+	a, _, err := c.api.MergeRequestApprovals.GetConfiguration(pid, mr.IID)
+	if err == nil {
+		switch {
+		case a.ApprovalsRequired > 0 && a.ApprovalsLeft == 0, a.ApprovalsRequired == 0 && len(a.ApprovedBy) > 0:
+			pr.Review = vcs.ReviewApproved
+		case a.ApprovalsLeft > 0:
+			pr.Review = vcs.ReviewRequired
+		}
+	}
+
+	return pr, nil
+}
+
+// state returns the state of an issue or merge request the way other
+// providers name it.
+func state(s string) string {
+	if s == "opened" {
+		return "open"
+	}
+	return s
+}
+
+// Issue returns the issue with the given number.
+func (c *Client) Issue(owner string, name string, number int) (*vcs.Issue, error) {
+	v, resp, err := c.api.Issues.GetIssue(owner+"/"+name, number)
+	if err != nil {
+		return nil, notFound(err, resp, "issue", number)
+	}
+
+	issue := c.issueFromAPI(v)
+	return &issue, nil
+}
+
+func (c *Client) issueFromAPI(v *gitlab.Issue) vcs.Issue {
+	issue := vcs.Issue{
+		ID:        v.IID,
+		Body:      v.Description,
+		Title:     v.Title,
+		State:     state(v.State),
+		CreatedAt: *v.CreatedAt,
+		URL:       v.WebURL,
+	}
+	if v.Author != nil {
+		issue.Author = v.Author.Username
+	}
+	if v.Milestone != nil {
+		issue.Milestone = v.Milestone.Title
+	}
+	for _, l := range v.Labels {
+		issue.Labels = append(issue.Labels, vcs.Label{
+			Name:  l,
+			Color: c.colorForLabel(l),
+		})
+	}
+
+	return issue
+}
+
+// IssueComments returns the comments of the issue with the given number.
+func (c *Client) IssueComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	var comments []vcs.Comment
+	for page := 1; ; page++ {
+		notes, resp, err := c.api.Notes.ListIssueNotes(owner+"/"+name, number, &gitlab.ListIssueNotesOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    page,
+				PerPage: 100,
+			},
+			OrderBy: gitlab.String("created_at"),
+			Sort:    gitlab.String("asc"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, commentsFromNotes(notes, c.webURL(owner, name)+fmt.Sprintf("/-/issues/%d", number))...)
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// PullRequestComments returns the comments of the merge request with the given
+// number.
+func (c *Client) PullRequestComments(owner string, name string, number int) ([]vcs.Comment, error) {
+	var comments []vcs.Comment
+	for page := 1; ; page++ {
+		notes, resp, err := c.api.Notes.ListMergeRequestNotes(owner+"/"+name, number, &gitlab.ListMergeRequestNotesOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    page,
+				PerPage: 100,
+			},
+			OrderBy: gitlab.String("created_at"),
+			Sort:    gitlab.String("asc"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, commentsFromNotes(notes, c.webURL(owner, name)+fmt.Sprintf("/-/merge_requests/%d", number))...)
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// commentsFromNotes converts notes to comments, leaving out the notes GitLab
+// creates for events like label changes.
+func commentsFromNotes(notes []*gitlab.Note, u string) []vcs.Comment {
+	var comments []vcs.Comment
+	for _, n := range notes {
+		if n.System {
+			continue
+		}
+
+		comment := vcs.Comment{
+			Author: n.Author.Username,
+			Body:   n.Body,
+			URL:    fmt.Sprintf("%s#note_%d", u, n.ID),
+		}
+		if n.CreatedAt != nil {
+			comment.CreatedAt = *n.CreatedAt
+		}
+		comments = append(comments, comment)
+	}
+	return comments
+}
+
+// CommitsSince compares the latest release of a repository to its default
+// branch. It returns the latest max commits and the total amount of commits
+// since the release.
+func (c *Client) CommitsSince(repo vcs.Repo, max int) ([]vcs.Commit, int, error) {
+	cmp, _, err := c.api.Repositories.Compare(repo.NameWithOwner, &gitlab.CompareOptions{
+		From: gitlab.String(repo.LastRelease.TagName),
+		To:   gitlab.String(repo.DefaultBranch),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// compared commits are sorted oldest first
+	var commits []vcs.Commit
+	for i := len(cmp.Commits) - 1; i >= 0; i-- {
+		if max > 0 && len(commits) >= max {
+			break
+		}
+		v := cmp.Commits[i]
+		commits = append(commits, vcs.Commit{
+			ID:              v.ID,
+			MessageHeadline: strings.ReplaceAll(v.Title, "\u00A0", " "),
+			CommittedAt:     *v.CommittedDate,
+			Author:          v.AuthorName,
+			URL:             v.WebURL,
+		})
+	}
+
+	return commits, len(cmp.Commits), nil
+}
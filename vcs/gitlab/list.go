@@ -0,0 +1,37 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/muesli/gitty/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// orderBy returns the GitLab field to order issues and merge requests by.
+func orderBy(sort vcs.SortOrder) string {
+	if sort == vcs.SortUpdated {
+		return "updated_at"
+	}
+	return "created_at"
+}
+
+// withQuery sets a query parameter of a request.
+func withQuery(key, value string) gitlab.RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		q := req.URL.Query()
+		q.Set(key, value)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// notFound turns a 404 response into vcs.ErrNotFound. Other errors are
+// returned as they are.
+func notFound(err error, resp *gitlab.Response, kind string, number int) error {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %d %w", kind, number, vcs.ErrNotFound)
+	}
+	return err
+}
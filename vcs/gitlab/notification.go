@@ -0,0 +1,71 @@
+package gitlab
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/muesli/gitty/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Notifications returns the pending to-dos of the authenticated user.
+func (c *Client) Notifications() ([]vcs.Notification, error) {
+	var notifications []vcs.Notification
+	opts := &gitlab.ListTodosOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		State: gitlab.String("pending"),
+	}
+	for {
+		todos, resp, err := c.api.Todos.ListTodos(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range todos {
+			n := vcs.Notification{
+				ID:     strconv.Itoa(v.ID),
+				Type:   vcs.SubjectType(string(v.TargetType)),
+				Reason: strings.ReplaceAll(string(v.ActionName), "_", " "),
+				URL:    v.TargetURL,
+			}
+			if v.Project != nil {
+				n.Repo = v.Project.PathWithNamespace
+			}
+			if v.Target != nil {
+				n.Title = v.Target.Title
+			}
+			if n.Title == "" {
+				n.Title = v.Body
+			}
+			if v.CreatedAt != nil {
+				n.UpdatedAt = *v.CreatedAt
+			}
+			notifications = append(notifications, n)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationRead marks a to-do as done.
+func (c *Client) MarkNotificationRead(id string) error {
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+	_, err = c.api.Todos.MarkTodoAsDone(i)
+	return err
+}
+
+// MarkAllNotificationsRead marks all to-dos as done.
+func (c *Client) MarkAllNotificationsRead() error {
+	_, err := c.api.Todos.MarkAllTodosAsDone()
+	return err
+}
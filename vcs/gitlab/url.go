@@ -0,0 +1,57 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func (c *Client) webURL(owner string, name string) string {
+	return fmt.Sprintf("https://%s/%s/%s", c.host, owner, name)
+}
+
+// RepositoryURL returns the URL to the given repository.
+func (c *Client) RepositoryURL(owner string, name string) string {
+	return c.webURL(owner, name)
+}
+
+// BranchURL returns the URL to the given branch.
+func (c *Client) BranchURL(owner string, name string, branch string) string {
+	return c.webURL(owner, name) + "/-/tree/" + vcs.EscapeRef(branch)
+}
+
+// CommitURL returns the URL to the commit with the given SHA.
+func (c *Client) CommitURL(owner string, name string, sha string) string {
+	return c.webURL(owner, name) + "/-/commit/" + url.PathEscape(sha)
+}
+
+// ReleaseURL returns the URL to the release of the given tag.
+func (c *Client) ReleaseURL(owner string, name string, tag string) string {
+	return c.webURL(owner, name) + "/-/releases/" + url.PathEscape(tag)
+}
+
+// CompareURL returns the URL comparing head with base.
+func (c *Client) CompareURL(owner string, name string, base string, head string) string {
+	return c.webURL(owner, name) + "/-/compare/" + vcs.EscapeRef(base) + "..." + vcs.EscapeRef(head)
+}
+
+// PipelinesURL returns the URL to the pipelines of the given branch.
+func (c *Client) PipelinesURL(owner string, name string, branch string) string {
+	return c.webURL(owner, name) + "/-/pipelines?ref=" + url.QueryEscape(branch)
+}
+
+// NewPullRequestURL returns the URL to open a merge request merging head into
+// base.
+func (c *Client) NewPullRequestURL(owner string, name string, base string, head string) string {
+	q := url.Values{}
+	q.Set("merge_request[source_branch]", head)
+	q.Set("merge_request[target_branch]", base)
+	return c.webURL(owner, name) + "/-/merge_requests/new?" + q.Encode()
+}
+
+// PullRequestRef returns the ref pointing at the head of the merge request with
+// the given number, which also covers merge requests from forks.
+func (c *Client) PullRequestRef(number int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", number)
+}
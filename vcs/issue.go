@@ -1,14 +1,32 @@
 package vcs
 
 import (
+	"errors"
 	"time"
 )
 
-// Issue represents an issue.
+// ErrNotFound is returned if an issue or pull request doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Issue represents an issue. State is either open or closed.
 type Issue struct {
 	ID        int
 	Body      string
 	Title     string
+	Author    string
+	State     string
 	Labels    Labels
+	Milestone string
 	CreatedAt time.Time
+	URL       string
+}
+
+// NewIssue describes an issue to be created. Labels, assignees and the
+// milestone are referred to by their names.
+type NewIssue struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+	Milestone string
 }
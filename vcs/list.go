@@ -0,0 +1,55 @@
+package vcs
+
+// SortOrder is the order items of a list request are retrieved in.
+type SortOrder string
+
+const (
+	// SortCreated lists the most recently created items first.
+	SortCreated SortOrder = "created"
+	// SortUpdated lists the most recently updated items first.
+	SortUpdated SortOrder = "updated"
+)
+
+// ListOptions limits, filters and sorts the items retrieved by a list request.
+// Providers that can't sort by the requested field return items in their
+// default order.
+type ListOptions struct {
+	// Limit is the max amount of items to retrieve, all of them if zero.
+	Limit int
+	// Sort is the order of the items, SortCreated if empty.
+	Sort SortOrder
+	// Milestone only retrieves the issues of the milestone with this title.
+	Milestone string
+}
+
+// PerPage returns the page size to request from an API allowing up to max
+// items per page.
+func (o ListOptions) PerPage(max int) int {
+	if o.Limit > 0 && o.Limit < max {
+		return o.Limit
+	}
+	return max
+}
+
+// Done reports whether n items satisfy the limit.
+func (o ListOptions) Done(n int) bool {
+	return o.Limit > 0 && n >= o.Limit
+}
+
+// UnknownTotal is returned as the total amount of items if an API didn't
+// report it and not all items were retrieved.
+const UnknownTotal = -1
+
+// Total returns the total amount of items an API reported, e.g. in a X-Total
+// header. APIs may omit it for very large result sets, in which case the
+// retrieved items are only the total if there are no more of them.
+func Total(reported, retrieved int, more bool) int {
+	switch {
+	case reported >= retrieved && reported > 0:
+		return reported
+	case !more:
+		return retrieved
+	default:
+		return UnknownTotal
+	}
+}
@@ -0,0 +1,43 @@
+package vcs
+
+import "testing"
+
+func TestListOptions(t *testing.T) {
+	tt := []struct {
+		limit   int
+		perPage int
+		done    bool
+	}{
+		{0, 100, false},
+		{10, 10, true},
+		{250, 100, false},
+		{50, 50, true},
+	}
+
+	for _, test := range tt {
+		o := ListOptions{Limit: test.limit}
+		if p := o.PerPage(100); p != test.perPage {
+			t.Errorf("Expected page size %d for limit %d, got %d", test.perPage, test.limit, p)
+		}
+		if d := o.Done(50); d != test.done {
+			t.Errorf("Expected done to be %v for 50 of %d items, got %v", test.done, test.limit, d)
+		}
+	}
+}
+
+func TestTotal(t *testing.T) {
+	if n := Total(120, 10, true); n != 120 {
+		t.Errorf("Expected the reported total of 120, got %d", n)
+	}
+
+	// the total is missing for large result sets
+	if n := Total(0, 10, true); n != UnknownTotal {
+		t.Errorf("Expected an unknown total, got %d", n)
+	}
+	if n := Total(0, 10, false); n != 10 {
+		t.Errorf("Expected the retrieved amount of 10, got %d", n)
+	}
+	if n := Total(0, 0, false); n != 0 {
+		t.Errorf("Expected no items, got %d", n)
+	}
+}
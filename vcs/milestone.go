@@ -0,0 +1,36 @@
+package vcs
+
+import (
+	"sort"
+	"time"
+)
+
+// Milestone represents an open milestone.
+type Milestone struct {
+	ID           int
+	Title        string
+	DueOn        time.Time
+	OpenIssues   int
+	ClosedIssues int
+	URL          string
+}
+
+// Progress returns the share of closed issues, between 0 and 1.
+func (m Milestone) Progress() float64 {
+	total := m.OpenIssues + m.ClosedIssues
+	if total == 0 {
+		return 0
+	}
+	return float64(m.ClosedIssues) / float64(total)
+}
+
+// SortMilestones sorts milestones by due date, milestones without a due date
+// last.
+func SortMilestones(milestones []Milestone) {
+	sort.SliceStable(milestones, func(i, j int) bool {
+		if milestones[i].DueOn.IsZero() || milestones[j].DueOn.IsZero() {
+			return !milestones[i].DueOn.IsZero()
+		}
+		return milestones[i].DueOn.Before(milestones[j].DueOn)
+	})
+}
@@ -0,0 +1,44 @@
+package vcs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMilestoneProgress(t *testing.T) {
+	tt := []struct {
+		open, closed int
+		exp          float64
+	}{
+		{0, 0, 0},
+		{4, 0, 0},
+		{1, 3, 0.75},
+		{0, 2, 1},
+	}
+
+	for _, test := range tt {
+		m := Milestone{OpenIssues: test.open, ClosedIssues: test.closed}
+		if p := m.Progress(); p != test.exp {
+			t.Errorf("Expected progress %v for %d/%d, got %v", test.exp, test.closed, test.open+test.closed, p)
+		}
+	}
+}
+
+func TestSortMilestones(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	milestones := []Milestone{
+		{Title: "someday"},
+		{Title: "later", DueOn: now.Add(48 * time.Hour)},
+		{Title: "backlog"},
+		{Title: "soon", DueOn: now.Add(time.Hour)},
+	}
+
+	SortMilestones(milestones)
+
+	exp := []string{"soon", "later", "someday", "backlog"}
+	for i, m := range milestones {
+		if m.Title != exp[i] {
+			t.Errorf("Expected %s at position %d, got %s", exp[i], i, m.Title)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package vcs
+
+import (
+	"strings"
+	"time"
+)
+
+// Notification is an unread notification, or a pending to-do on GitLab. Type
+// is the kind of its subject, e.g. issue or pull request, Reason why the user
+// got notified.
+type Notification struct {
+	ID        string
+	Repo      string
+	Title     string
+	Type      string
+	Reason    string
+	UpdatedAt time.Time
+	URL       string
+}
+
+// SubjectType turns a camel-cased subject type like PullRequest into
+// "pull request".
+func SubjectType(t string) string {
+	var s strings.Builder
+	for i, r := range t {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			s.WriteRune(' ')
+		}
+		s.WriteRune(r)
+	}
+	return strings.ToLower(s.String())
+}
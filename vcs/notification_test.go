@@ -0,0 +1,18 @@
+package vcs
+
+import "testing"
+
+func TestSubjectType(t *testing.T) {
+	tt := map[string]string{
+		"Issue":        "issue",
+		"PullRequest":  "pull request",
+		"MergeRequest": "merge request",
+		"":             "",
+	}
+
+	for in, expected := range tt {
+		if s := SubjectType(in); s != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, in, s)
+		}
+	}
+}
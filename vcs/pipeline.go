@@ -0,0 +1,84 @@
+package vcs
+
+import (
+	"time"
+)
+
+// PipelineStatus is the state of a CI run.
+type PipelineStatus string
+
+// Pipeline states.
+const (
+	PipelineSuccess  PipelineStatus = "success"
+	PipelineFailed   PipelineStatus = "failed"
+	PipelineRunning  PipelineStatus = "running"
+	PipelinePending  PipelineStatus = "pending"
+	PipelineCanceled PipelineStatus = "canceled"
+	PipelineSkipped  PipelineStatus = "skipped"
+)
+
+// Pipeline represents the latest CI run of a branch. Providers running
+// several workflows per commit get summarized into a single Pipeline.
+type Pipeline struct {
+	Branch     string
+	Name       string
+	Status     PipelineStatus
+	FailedJob  string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	URL        string
+}
+
+// statusPriority ranks pipeline states when summarizing several runs, the
+// most important first.
+var statusPriority = []PipelineStatus{
+	PipelineFailed,
+	PipelineRunning,
+	PipelinePending,
+	PipelineCanceled,
+	PipelineSuccess,
+	PipelineSkipped,
+}
+
+// SummarizePipelines combines several runs of the same commit into one
+// Pipeline. Its status is the most important of all runs, e.g. a single
+// failed run fails the whole Pipeline.
+func SummarizePipelines(branch string, runs []Pipeline) Pipeline {
+	p := Pipeline{Branch: branch}
+	if len(runs) == 0 {
+		return p
+	}
+
+	rank := func(s PipelineStatus) int {
+		for i, v := range statusPriority {
+			if v == s {
+				return i
+			}
+		}
+		return len(statusPriority)
+	}
+
+	var running bool
+	for i, r := range runs {
+		if i == 0 || rank(r.Status) < rank(p.Status) {
+			p.Status = r.Status
+			p.Name = r.Name
+			p.FailedJob = r.FailedJob
+			p.URL = r.URL
+		}
+
+		if !r.StartedAt.IsZero() && (p.StartedAt.IsZero() || r.StartedAt.Before(p.StartedAt)) {
+			p.StartedAt = r.StartedAt
+		}
+		if r.Status == PipelineRunning || r.Status == PipelinePending {
+			running = true
+		} else if r.FinishedAt.After(p.FinishedAt) {
+			p.FinishedAt = r.FinishedAt
+		}
+	}
+	if running {
+		p.FinishedAt = time.Time{}
+	}
+
+	return p
+}
@@ -0,0 +1,35 @@
+package vcs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizePipelines(t *testing.T) {
+	start := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	p := SummarizePipelines("main", []Pipeline{
+		{Name: "lint", Status: PipelineSuccess, StartedAt: start, FinishedAt: start.Add(time.Minute)},
+		{Name: "build", Status: PipelineFailed, FailedJob: "test", StartedAt: start.Add(time.Second), FinishedAt: start.Add(5 * time.Minute)},
+		{Name: "docs", Status: PipelineSkipped},
+	})
+	if p.Branch != "main" || p.Status != PipelineFailed || p.Name != "build" || p.FailedJob != "test" {
+		t.Errorf("Unexpected summary: %+v", p)
+	}
+	if !p.StartedAt.Equal(start) || !p.FinishedAt.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("Unexpected time span: %s - %s", p.StartedAt, p.FinishedAt)
+	}
+
+	// a pipeline isn't finished while one of its runs is still going
+	p = SummarizePipelines("main", []Pipeline{
+		{Name: "lint", Status: PipelineSuccess, StartedAt: start, FinishedAt: start.Add(time.Minute)},
+		{Name: "build", Status: PipelineRunning, StartedAt: start},
+	})
+	if p.Status != PipelineRunning || !p.FinishedAt.IsZero() {
+		t.Errorf("Unexpected summary: %+v", p)
+	}
+
+	if p := SummarizePipelines("main", nil); p.Status != "" {
+		t.Errorf("Expected no status, got %s", p.Status)
+	}
+}
@@ -4,11 +4,64 @@ import (
 	"time"
 )
 
-// PullRequest represents a pull request.
+// PullRequest represents a pull request. State is either open, closed or
+// merged.
 type PullRequest struct {
 	ID        int
 	Body      string
 	Title     string
+	Author    string
+	State     string
 	Labels    Labels
 	CreatedAt time.Time
+	URL       string
+}
+
+// NewPullRequest describes a pull request to be created, merging the branch
+// Head into Base. Labels, assignees and the milestone are referred to by their
+// names.
+type NewPullRequest struct {
+	Title     string
+	Body      string
+	Head      string
+	Base      string
+	Labels    []string
+	Assignees []string
+	Milestone string
+}
+
+// ReviewStatus is the review decision of a pull request.
+type ReviewStatus string
+
+// Review states.
+const (
+	ReviewApproved         ReviewStatus = "approved"
+	ReviewChangesRequested ReviewStatus = "changes requested"
+	ReviewRequired         ReviewStatus = "review required"
+)
+
+// MergeStatus describes whether a pull request can be merged.
+type MergeStatus string
+
+// Merge states.
+const (
+	MergeClean       MergeStatus = "mergeable"
+	MergeConflicting MergeStatus = "conflicting"
+	MergeUnknown     MergeStatus = "unknown"
+)
+
+// PullRequestStatus is a pull request along with everything that decides
+// whether it's ready to be merged. Review and CI are empty if the pull request
+// hasn't been reviewed or the repository doesn't use CI. HeadRepo is empty if
+// the repository the changes come from has been deleted.
+type PullRequestStatus struct {
+	PullRequest
+	Branch       string
+	HeadRepo     string
+	HeadCloneURL string
+	Draft        bool
+	Review       ReviewStatus
+	CI           PipelineStatus
+	Comments     int
+	Mergeable    MergeStatus
 }
@@ -0,0 +1,301 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRetries is how often a rate limited request gets retried.
+	maxRetries = 5
+	// maxWait is the longest we're willing to wait for a rate limit to reset.
+	maxWait = 2 * time.Minute
+	// reserveRatio is the share of the quota we never use up, so other tools
+	// using the same token keep working.
+	reserveRatio = 20
+)
+
+// GitHub keeps separate quotas per resource, e.g. for its REST and GraphQL
+// APIs. Other hosts only have a core quota.
+const (
+	coreResource    = "core"
+	graphQLResource = "graphql"
+	searchResource  = "search"
+)
+
+// RateLimit describes the API quota of a host.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Known returns whether the host reported a rate limit at all.
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// reserve returns how many requests of the quota we don't use.
+func (r RateLimit) reserve() int {
+	return r.Limit / reserveRatio
+}
+
+// RateLimitError is returned when the rate limit of a host is (almost)
+// exhausted and won't reset in a reasonable amount of time.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "rate limit exceeded"
+	}
+	return fmt.Sprintf("rate limit exceeded, resets at %s", e.Reset.Local().Format("15:04:05"))
+}
+
+// sleep waits for d or until ctx is done. It's a variable so tests don't have
+// to wait.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// RateLimitTransport is an http.RoundTripper that keeps track of a host's
+// rate limits. It backs off when requests get rate limited and stops before
+// a quota is used up.
+type RateLimitTransport struct {
+	Base http.RoundTripper
+
+	mu     sync.Mutex
+	limits map[string]RateLimit
+}
+
+// NewRateLimitTransport returns a RateLimitTransport wrapping base. If base is
+// nil, http.DefaultTransport is used.
+func NewRateLimitTransport(base http.RoundTripper) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RateLimitTransport{Base: base, limits: map[string]RateLimit{}}
+}
+
+// RateLimit returns the core rate limit last reported by the host.
+func (t *RateLimitTransport) RateLimit() RateLimit {
+	return t.resourceLimit(coreResource)
+}
+
+func (t *RateLimitTransport) resourceLimit(resource string) RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.limits[resource]
+}
+
+// requestResource returns the resource whose quota a request uses up.
+func requestResource(req *http.Request) string {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/graphql"):
+		return graphQLResource
+	case strings.Contains(req.URL.Path, "/search/"):
+		return searchResource
+	default:
+		return coreResource
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := requestResource(req)
+	if err := t.waitForQuota(req.Context(), resource); err != nil {
+		return nil, err
+	}
+
+	r := req
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.Base.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		t.update(resource, resp.Header)
+
+		wait, limited := backoff(resp, attempt)
+		if !limited {
+			return resp, nil
+		}
+		rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= maxRetries || !rewindable {
+			// give up and let the client deal with the response
+			return resp, nil
+		}
+		if wait > maxWait {
+			resp.Body.Close() //nolint:errcheck
+			return nil, &RateLimitError{Reset: time.Now().Add(wait)}
+		}
+
+		resp.Body.Close() //nolint:errcheck
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForQuota blocks until the reset of a resource's (almost) exhausted rate
+// limit, or returns an error if that would take too long.
+func (t *RateLimitTransport) waitForQuota(ctx context.Context, resource string) error {
+	l := t.resourceLimit(resource)
+	if !l.Known() || l.Remaining > l.reserve() {
+		return nil
+	}
+
+	wait := time.Until(l.Reset)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxWait {
+		return &RateLimitError{Reset: l.Reset}
+	}
+	if err := sleep(ctx, wait); err != nil {
+		return err
+	}
+
+	// assume the quota has been replenished
+	t.mu.Lock()
+	l.Remaining = l.Limit
+	t.limits[resource] = l
+	t.mu.Unlock()
+	return nil
+}
+
+// RetryAfterRateLimit waits before retrying a GraphQL query that got rate
+// limited without the transport noticing, i.e. failed with a RATE_LIMITED
+// error in a successful response. If the GraphQL quota is exhausted, the
+// retried query waits for its reset instead. It returns a RateLimitError once
+// attempt exceeds the retries.
+func (t *RateLimitTransport) RetryAfterRateLimit(ctx context.Context, attempt int) error {
+	l := t.resourceLimit(graphQLResource)
+	if attempt >= maxRetries {
+		return &RateLimitError{Reset: l.Reset}
+	}
+	if l.Known() && l.Remaining <= l.reserve() {
+		return nil
+	}
+
+	// exponential backoff, starting at a second
+	return sleep(ctx, jitter(time.Second<<uint(attempt)))
+}
+
+// update records the rate limit reported in the response headers. GitHub and
+// Gitea use the X-RateLimit-* headers, GitLab the RateLimit-* ones. GitHub
+// also names the resource the quota belongs to.
+func (t *RateLimitTransport) update(resource string, h http.Header) {
+	limit, ok := headerInt(h, "X-RateLimit-Limit", "RateLimit-Limit")
+	if !ok {
+		return
+	}
+	remaining, _ := headerInt(h, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	reset, _ := headerInt(h, "X-RateLimit-Reset", "RateLimit-Reset")
+
+	if r := h.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+
+	l := RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+	}
+	if reset > 0 {
+		l.Reset = time.Unix(int64(reset), 0)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.limits[resource] = l
+}
+
+// backoff returns whether a response got rate limited and how long to wait
+// before retrying it.
+func backoff(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusForbidden:
+		// GitHub signals exceeded primary and secondary rate limits with a 403
+		if resp.Header.Get("Retry-After") == "" &&
+			resp.Header.Get("X-RateLimit-Remaining") != "0" &&
+			!mentionsRateLimit(resp) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if s, ok := headerInt(resp.Header, "Retry-After"); ok {
+		return jitter(time.Duration(s) * time.Second), true
+	}
+	if reset, ok := headerInt(resp.Header, "X-RateLimit-Reset", "RateLimit-Reset"); ok {
+		if d := time.Until(time.Unix(int64(reset), 0)); d > 0 {
+			return jitter(d), true
+		}
+	}
+
+	// exponential backoff, starting at a second
+	return jitter(time.Second << uint(attempt)), true
+}
+
+// mentionsRateLimit returns whether the body of resp mentions a rate limit,
+// which is the only hint for some of GitHub's secondary rate limits. The body
+// stays readable.
+func mentionsRateLimit(resp *http.Response) bool {
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	body := strings.ToLower(string(b))
+	return strings.Contains(body, "rate limit") || strings.Contains(body, "abuse")
+}
+
+// jitter adds up to 25% to d, so concurrent requests don't retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/4+1)) //nolint:gosec
+}
+
+func headerInt(h http.Header, keys ...string) (int, bool) {
+	for _, k := range keys {
+		if v := h.Get(k); v != "" {
+			i, err := strconv.Atoi(v)
+			if err == nil {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
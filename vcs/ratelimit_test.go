@@ -0,0 +1,261 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+
+	var waits []time.Duration
+	prev := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = prev })
+	return &waits
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gitlab" {
+			w.Header().Set("RateLimit-Limit", "2000")
+			w.Header().Set("RateLimit-Remaining", "1999")
+		} else {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "4321")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		}
+	}))
+	defer ts.Close()
+
+	tr := NewRateLimitTransport(nil)
+	client := &http.Client{Transport: tr}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	l := tr.RateLimit()
+	if l.Limit != 5000 || l.Remaining != 4321 || l.Reset.Unix() != reset {
+		t.Errorf("Unexpected rate limit: %+v", l)
+	}
+
+	resp, err = client.Get(ts.URL + "/gitlab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	l = tr.RateLimit()
+	if l.Limit != 2000 || l.Remaining != 1999 || !l.Reset.IsZero() {
+		t.Errorf("Unexpected rate limit: %+v", l)
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  func(w http.ResponseWriter)
+		minDur time.Duration
+	}{
+		{"too many requests", func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, 3 * time.Second},
+		{"secondary rate limit", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+		}, time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			waits := noSleep(t)
+
+			var calls int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				b, _ := io.ReadAll(r.Body)
+				if string(b) != "query" {
+					t.Errorf("Expected body to be replayed, got %q", b)
+				}
+				if calls < 3 {
+					test.limit(w)
+					return
+				}
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer ts.Close()
+
+			client := &http.Client{Transport: NewRateLimitTransport(nil)}
+			resp, err := client.Post(ts.URL, "text/plain", strings.NewReader("query"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close() //nolint:errcheck
+
+			if resp.StatusCode != http.StatusOK || calls != 3 {
+				t.Errorf("Expected success after 3 calls, got %d after %d", resp.StatusCode, calls)
+			}
+			if len(*waits) != 2 {
+				t.Fatalf("Expected 2 back-offs, got %v", *waits)
+			}
+			for _, d := range *waits {
+				if d < test.minDur || d > test.minDur*3 {
+					t.Errorf("Unexpected back-off of %s", d)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitForbidden(t *testing.T) {
+	noSleep(t)
+
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: NewRateLimitTransport(nil)}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint:errcheck
+
+	// a regular 403 gets passed through untouched
+	if calls != 1 || !strings.Contains(string(b), "not accessible") {
+		t.Errorf("Unexpected response %q after %d calls", b, calls)
+	}
+}
+
+func TestRateLimitReserve(t *testing.T) {
+	waits := noSleep(t)
+
+	var calls int
+	reset := time.Now().Add(30 * time.Second).Unix()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	}))
+	defer ts.Close()
+
+	tr := NewRateLimitTransport(nil)
+	client := &http.Client{Transport: tr}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() //nolint:errcheck
+	}
+
+	// the reserve was reached, so we waited for the reset
+	if calls != 2 || len(*waits) != 1 {
+		t.Errorf("Expected to wait once for the reset, waited %v", *waits)
+	}
+
+	// don't wait for a reset that's too far away
+	reset = time.Now().Add(time.Hour).Unix()
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	_, err = client.Get(ts.URL)
+	var rerr *RateLimitError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected no request to be sent, got %d calls", calls)
+	}
+}
+
+// An exhausted GraphQL quota doesn't hold back REST requests.
+func TestRateLimitResources(t *testing.T) {
+	waits := noSleep(t)
+
+	reset := time.Now().Add(time.Hour).Unix()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+		} else {
+			w.Header().Set("X-RateLimit-Remaining", "4000")
+			w.Header().Set("X-RateLimit-Resource", "core")
+		}
+	}))
+	defer ts.Close()
+
+	tr := NewRateLimitTransport(nil)
+	client := &http.Client{Transport: tr}
+	for _, path := range []string{"/graphql", "/notifications", "/notifications"} {
+		resp, err := client.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() //nolint:errcheck
+	}
+	if len(*waits) != 0 {
+		t.Errorf("Expected REST requests not to wait, waited %v", *waits)
+	}
+	if l := tr.RateLimit(); l.Remaining != 4000 {
+		t.Errorf("Expected the core quota, got %+v", l)
+	}
+
+	_, err := client.Get(ts.URL + "/graphql")
+	var rerr *RateLimitError
+	if !errors.As(err, &rerr) {
+		t.Errorf("Expected a RateLimitError for the exhausted GraphQL quota, got %v", err)
+	}
+}
+
+func TestRetryAfterRateLimit(t *testing.T) {
+	waits := noSleep(t)
+
+	tr := NewRateLimitTransport(nil)
+	if err := tr.RetryAfterRateLimit(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(*waits) != 1 {
+		t.Errorf("Expected to back off once, waited %v", *waits)
+	}
+
+	// the next request waits for the reset of an exhausted quota
+	tr.limits[graphQLResource] = RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(time.Minute)}
+	if err := tr.RetryAfterRateLimit(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(*waits) != 1 {
+		t.Errorf("Expected no back off for an exhausted quota, waited %v", *waits)
+	}
+
+	var rerr *RateLimitError
+	if err := tr.RetryAfterRateLimit(context.Background(), maxRetries); !errors.As(err, &rerr) {
+		t.Errorf("Expected a RateLimitError, got %v", err)
+	}
+}
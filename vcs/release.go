@@ -4,11 +4,14 @@ import (
 	"time"
 )
 
-// Release represents a release.
+// Release represents a release. CommitCount is the amount of commits that
+// landed on the default branch since the release, of which CommitsSince may
+// only contain the latest.
 type Release struct {
 	Name         string
 	TagName      string
 	PublishedAt  time.Time
 	URL          string
 	CommitsSince []Commit
+	CommitCount  int
 }
@@ -1,11 +1,15 @@
 package vcs
 
+import "strings"
+
 // Repo represents a repository.
 type Repo struct {
+	Host          string
 	Owner         string
 	Name          string
 	NameWithOwner string
 	URL           string
+	DefaultBranch string
 	Description   string
 	Stargazers    int
 	Watchers      int
@@ -28,3 +32,18 @@ func ReposWithRelease(repos []Repo) []Repo {
 
 	return r
 }
+
+// OwnedBy reports whether the repository with the given name and owner, e.g.
+// "muesli/gitty", belongs to one of owners.
+func OwnedBy(nameWithOwner string, owners []string) bool {
+	i := strings.LastIndex(nameWithOwner, "/")
+	if i < 0 {
+		return false
+	}
+	for _, o := range owners {
+		if strings.EqualFold(nameWithOwner[:i], o) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package vcs
+
+// Summary is the latest activity of a repository, as retrieved in a single
+// request: its most recent issues and pull requests along with their total
+// amount, its most recently updated branches, and its open milestones.
+type Summary struct {
+	Repo             Repo
+	Issues           []Issue
+	IssueCount       int
+	PullRequests     []PullRequest
+	PullRequestCount int
+	Branches         []Branch
+	Milestones       []Milestone
+}
@@ -0,0 +1,16 @@
+package vcs
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EscapeRef escapes a branch or tag name for use in a URL path. Slashes are
+// kept, as all hosts expect nested refs as nested paths.
+func EscapeRef(ref string) string {
+	s := strings.Split(ref, "/")
+	for i := range s {
+		s[i] = url.PathEscape(s[i])
+	}
+	return strings.Join(s, "/")
+}
@@ -0,0 +1,21 @@
+package vcs
+
+import "testing"
+
+func TestEscapeRef(t *testing.T) {
+	tt := []struct {
+		ref string
+		exp string
+	}{
+		{"master", "master"},
+		{"feature/long-branch-name", "feature/long-branch-name"},
+		{"fix #12", "fix%20%2312"},
+		{"v1.0.0?", "v1.0.0%3F"},
+	}
+
+	for _, test := range tt {
+		if s := EscapeRef(test.ref); s != test.exp {
+			t.Errorf("Expected %s for %s, got %s", test.exp, test.ref, s)
+		}
+	}
+}
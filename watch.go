@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// refreshing more often than this would quickly exhaust API rate limits
+	minWatchInterval = 30 * time.Second
+	maxWatchInterval = 15 * time.Minute
+)
+
+// watchState remembers what the previous refresh of a watched repository
+// showed.
+type watchState struct {
+	prev     *Overview
+	prevTime time.Time
+
+	// the newest issue and pull request shown so far
+	newestIssue       listMark
+	newestPullRequest listMark
+}
+
+// newWatchState starts watching a repository from the last run of gitty.
+// Watching never moves the last run.
+func newWatchState(host, owner, name string) *watchState {
+	w := &watchState{}
+	if s := loadSnapshot(overviewSnapshots, host, owner+"/"+name); s != nil {
+		w.prev, w.prevTime = s.overview(), s.Time
+		w.newestIssue, w.newestPullRequest = s.NewestIssue, s.NewestPullRequest
+	}
+	return w
+}
+
+// compare marks everything in the overview that changed since the previous
+// refresh. With --since-last-run, it keeps showing everything that changed
+// since the last run instead.
+func (w *watchState) compare(o *Overview) {
+	o.changes = diffOverview(w.prev, o, w.newestIssue, w.newestPullRequest)
+	o.lastRun = w.prevTime
+	if *sinceLastRun {
+		if w.prev != nil {
+			o.onlyChanges()
+		}
+		return
+	}
+
+	cur := *o
+	w.prev, w.prevTime = &cur, now()
+	for _, v := range o.Issues {
+		w.newestIssue.add(v.ID, v.CreatedAt)
+	}
+	for _, v := range o.PullRequests {
+		w.newestPullRequest.add(v.ID, v.CreatedAt)
+	}
+}
+
+// watchRepository periodically fetches and redraws the overview of a
+// repository, highlighting everything that changed since the previous refresh.
+func watchRepository(client Client, path, remote, host, owner, name string) {
+	interval := *watch
+	if interval < minWatchInterval {
+		interval = minWatchInterval
+	}
+
+	statusStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorTooltip))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+
+	out := termenv.NewOutput(os.Stdout)
+	var view bytes.Buffer
+	delay := interval
+	state := newWatchState(host, owner, name)
+
+	for {
+		o, err := fetchOverview(client, path, remote, host, owner, name)
+		var status string
+		if err != nil {
+			// keep showing the last state and back off until the provider
+			// recovers
+			delay = backoff(delay, interval)
+			status = errorStyle.Render(fmt.Sprintf("Refresh failed: %s", err))
+		} else {
+			delay = interval
+			state.compare(o)
+
+			view.Reset()
+			if err := renderOverview(&view, o); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		out.ClearScreen()
+		fmt.Println(view.String())
+		if status != "" {
+			fmt.Println(status)
+		}
+		fmt.Println(statusStyle.Render(fmt.Sprintf("Last refresh %s, next in %s",
+			now().Format("15:04:05"), delay)))
+
+		time.Sleep(delay)
+	}
+}
+
+// backoff doubles the delay after a failed refresh, up to maxWatchInterval or
+// the configured interval, if that's longer.
+func backoff(delay, interval time.Duration) time.Duration {
+	limit := maxWatchInterval
+	if interval > limit {
+		limit = interval
+	}
+
+	delay *= 2
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestBackoff(t *testing.T) {
+	tt := []struct {
+		delay    time.Duration
+		interval time.Duration
+		exp      time.Duration
+	}{
+		{time.Minute, time.Minute, 2 * time.Minute},
+		{10 * time.Minute, time.Minute, maxWatchInterval},
+		// failures never poll more often than successes
+		{30 * time.Minute, 30 * time.Minute, 30 * time.Minute},
+		{20 * time.Minute, 20 * time.Minute, 20 * time.Minute},
+	}
+
+	for _, test := range tt {
+		if d := backoff(test.delay, test.interval); d != test.exp {
+			t.Errorf("Expected %s after %s with an interval of %s, got %s",
+				test.exp, test.delay, test.interval, d)
+		}
+	}
+}
+
+func TestWatchState(t *testing.T) {
+	setCacheDir(t)
+	compareWithLastRun(testOverview())
+
+	newOverview := func() *Overview {
+		o := testOverview()
+		o.Issues = append(o.Issues, vcs.Issue{ID: 99, Title: "New issue", CreatedAt: fixedNow})
+		return o
+	}
+
+	w := newWatchState("github.com", "muesli", "gitty")
+	o := newOverview()
+	w.compare(o)
+	if !o.changes.issue(99) {
+		t.Error("Expected the issue to be new since the last run")
+	}
+	o = newOverview()
+	w.compare(o)
+	if o.changes.issue(99) {
+		t.Error("Expected the issue not to be new since the previous refresh")
+	}
+
+	// watching doesn't move the last run
+	s := loadSnapshot(overviewSnapshots, "github.com", "muesli/gitty")
+	for _, id := range s.Issues {
+		if id == 99 {
+			t.Fatal("Expected the snapshot of the last run to be unchanged")
+		}
+	}
+
+	prev := *sinceLastRun
+	*sinceLastRun = true
+	t.Cleanup(func() { *sinceLastRun = prev })
+
+	w = newWatchState("github.com", "muesli", "gitty")
+	for i := 0; i < 2; i++ {
+		o = newOverview()
+		w.compare(o)
+		if len(o.Issues) != 1 || o.Issues[0].ID != 99 {
+			t.Errorf("Expected only the issue new since the last run, got %v", o.Issues)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/muesli/gitty/vcs"
+)
+
+// checkout summarizes a local git repository and its remote counterpart.
+type checkout struct {
+	Path         string
+	Host         string
+	Owner        string
+	Name         string
+	Branch       string
+	Stat         *trackStat
+	Dirty        bool
+	Issues       int
+	PullRequests int
+	Release      vcs.Release
+	Err          error
+}
+
+// clientCache creates at most one API client per host.
+type clientCache struct {
+	sync.Mutex
+	clients map[string]Client
+	errs    map[string]error
+}
+
+func (c *clientCache) get(host string) (Client, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if err, ok := c.errs[host]; ok {
+		return nil, err
+	}
+	if client, ok := c.clients[host]; ok {
+		return client, nil
+	}
+
+	client, err := guessClient(host)
+	if err != nil {
+		c.errs[host] = err
+		return nil, err
+	}
+	c.clients[host] = client
+	return client, nil
+}
+
+// discoverCheckouts returns the paths of all git repositories below root.
+func discoverCheckouts(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// skip directories we can't read
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		// .git is a file for worktrees and submodules
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			paths = append(paths, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	sort.Strings(paths)
+	return paths, err
+}
+
+// localState returns the current branch of a checkout, how it compares to its
+// upstream branch and whether the worktree has uncommitted changes.
+func localState(path string) (string, *trackStat, bool, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	var dirty bool
+	if wt, err := repo.Worktree(); err == nil {
+		if status, err := wt.Status(); err == nil {
+			dirty = !status.IsClean()
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", nil, dirty, err
+	}
+	if !head.Name().IsBranch() {
+		// detached HEAD
+		return head.Hash().String()[:7], nil, dirty, nil
+	}
+
+	branch := head.Name().Short()
+	b, err := repo.Branch(branch)
+	if err != nil || b.Remote == "" {
+		// no upstream branch
+		return branch, nil, dirty, nil //nolint:nilerr
+	}
+
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short()), true)
+	if err != nil {
+		return branch, nil, dirty, nil //nolint:nilerr
+	}
+
+	stat := &trackStat{}
+	stat.Ahead, stat.Behind, err = calculateTrackCount(repo, head.Hash(), remoteRef.Hash())
+	if err != nil {
+		return branch, nil, dirty, err
+	}
+	return branch, stat, dirty, nil
+}
+
+// fetchCheckout retrieves the local and remote state of a checkout.
+func fetchCheckout(clients *clientCache, path string) checkout {
+	c := checkout{Path: path}
+	c.Branch, c.Stat, c.Dirty, _ = localState(path)
+
+	var err error
+	c.Host, c.Owner, c.Name, _, err = parseRepo(path)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+
+	client, err := clients.get(c.Host)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+
+	// only the amounts get shown, an unknown one is at least the single
+	// retrieved item
+	_, c.Issues, err = client.Issues(c.Owner, c.Name, vcs.ListOptions{Limit: 1})
+	if err != nil {
+		c.Err = err
+		return c
+	}
+
+	_, c.PullRequests, err = client.PullRequests(c.Owner, c.Name, vcs.ListOptions{Limit: 1})
+	if err != nil {
+		c.Err = err
+		return c
+	}
+
+	r, err := client.Repository(c.Owner, c.Name)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	if r.LastRelease.TagName != "" {
+		// only the amount of commits gets shown
+		r.LastRelease.CommitsSince, r.LastRelease.CommitCount, err = commitsSinceRelease(client, r, 1)
+		if err != nil {
+			c.Err = err
+			return c
+		}
+	}
+	c.Release = r.LastRelease
+
+	return c
+}
+
+func parseWorkspace(root string) {
+	paths, err := discoverCheckouts(root)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	clients := &clientCache{
+		clients: map[string]Client{},
+		errs:    map[string]error{},
+	}
+
+	checkouts := make([]checkout, len(paths))
+	pool := newWorkPool(*concurrency)
+	prog := newProgress("Retrieving repositories")
+	prog.add(len(paths))
+	wg := &sync.WaitGroup{}
+	for i, path := range paths {
+		i, path := i, path
+		pool.Go(wg, func() {
+			defer prog.inc()
+			checkouts[i] = fetchCheckout(clients, path)
+		})
+	}
+	wg.Wait()
+	prog.finish()
+
+	printCheckouts(os.Stdout, root, checkouts)
+}
+
+func printCheckouts(w io.Writer, root string, checkouts []checkout) {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorMagenta))
+	pathStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorBlue))
+	branchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorCyan))
+	genericStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGray))
+	dirtyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+	countStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorDarkGray))
+	changesStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorGreen))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.colorRed))
+
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("%s %s", "🗂 ",
+		pluralize(len(checkouts), "repository in "+root, "repositories in "+root))))
+
+	// detect max width of paths and branch names
+	var pathWidth, branchWidth int
+	rel := make([]string, len(checkouts))
+	for i, c := range checkouts {
+		rel[i] = c.Path
+		if p, err := filepath.Rel(root, c.Path); err == nil {
+			rel[i] = p
+		}
+		if lipgloss.Width(rel[i]) > pathWidth {
+			pathWidth = lipgloss.Width(rel[i])
+		}
+		if lipgloss.Width(c.Branch) > branchWidth {
+			branchWidth = lipgloss.Width(c.Branch)
+		}
+	}
+	pathStyle = pathStyle.Width(pathWidth)
+	branchStyle = branchStyle.Width(branchWidth)
+
+	for i, c := range checkouts {
+		var s string
+		s += pathStyle.Render(rel[i])
+		s += genericStyle.Render(" ")
+		s += branchStyle.Render(c.Branch)
+		s += genericStyle.Render(" ")
+		if c.Stat != nil {
+			s += c.Stat.Render()
+		} else {
+			// no upstream branch to compare with
+			s += lipgloss.NewStyle().Width(statWidth).Render("")
+		}
+		s += genericStyle.Render(" ")
+		if c.Dirty {
+			s += dirtyStyle.Render("✎")
+		} else {
+			s += genericStyle.Render(" ")
+		}
+		s += genericStyle.Render(" ")
+
+		if c.Err != nil {
+			s += errorStyle.Render(c.Err.Error())
+			fmt.Fprintln(w, s)
+			continue
+		}
+
+		s += countStyle.Render(fmt.Sprintf("%s, %s",
+			pluralizeTotal(c.Issues, 1, "issue", "issues"),
+			pluralizeTotal(c.PullRequests, 1, "PR", "PRs")))
+		if c.Release.TagName != "" {
+			s += genericStyle.Render(", ")
+			s += changesStyle.Render(fmt.Sprintf("%d unreleased since %s",
+				c.Release.CommitCount, c.Release.TagName))
+		}
+
+		fmt.Fprintln(w, s)
+	}
+}
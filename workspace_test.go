@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/muesli/gitty/vcs"
+)
+
+func TestDiscoverCheckouts(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"b", "a", "nested/c", "b/vendor/d"} {
+		if _, err := git.PlainInit(filepath.Join(root, dir), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := discoverCheckouts(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// we don't descend into checkouts
+	exp := []string{
+		filepath.Join(root, "a"),
+		filepath.Join(root, "b"),
+		filepath.Join(root, "nested", "c"),
+	}
+	if !reflect.DeepEqual(paths, exp) {
+		t.Errorf("Expected %v, got %v", exp, paths)
+	}
+}
+
+func TestPrintCheckouts(t *testing.T) {
+	setupRenderTest(t, "dark")
+
+	root := "src"
+	checkouts := []checkout{
+		{
+			Path:         filepath.Join(root, "gitty"),
+			Branch:       "master",
+			Stat:         &trackStat{Ahead: 2},
+			Dirty:        true,
+			Issues:       12,
+			PullRequests: 1,
+			Release: vcs.Release{
+				TagName:      "v0.3.0",
+				CommitsSince: testCommits(),
+				CommitCount:  len(testCommits()),
+			},
+		},
+		{
+			Path:   filepath.Join(root, "termenv"),
+			Branch: "feature-branch",
+		},
+		{
+			Path:         filepath.Join(root, "kde"),
+			Branch:       "master",
+			Issues:       vcs.UnknownTotal,
+			PullRequests: vcs.UnknownTotal,
+		},
+		{
+			Path:   filepath.Join(root, "local"),
+			Branch: "main",
+			Err:    errors.New("no remote configured"),
+		},
+	}
+
+	var buf bytes.Buffer
+	printCheckouts(&buf, root, checkouts)
+	assertGolden(t, "workspace", buf.Bytes())
+}